@@ -1,17 +1,26 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 	gh "github.com/gorilla/handlers"
+	"sport-events-backend/internal/auth"
 	"sport-events-backend/internal/config"
 	"sport-events-backend/internal/handlers"
+	"sport-events-backend/internal/jobs"
+	"sport-events-backend/internal/mailer"
 	"sport-events-backend/internal/middleware"
+	"sport-events-backend/internal/notify"
+	"sport-events-backend/internal/policy"
+	"sport-events-backend/internal/repository"
+	"sport-events-backend/internal/webhooks"
 )
 func getEnvAsInt(name string, defaultVal int) int {
 	valStr := os.Getenv(name)
@@ -31,10 +40,40 @@ func main() {
 	}
 	// Iniciar conexión a la BD
 	config.InitDB()
-	jtw := os.Getenv("JWT_SECRET")
-	if jtw == "" {
-		log.Fatal("JWT_SECRET no está configurado")
+	// Claves de firma de los access tokens (RS256/EdDSA con JWT_KEYS_DIR, o HS256 heredado)
+	if err := auth.InitKeys(); err != nil {
+		log.Fatal("Error cargando las claves JWT: ", err)
 	}
+	// Envío de emails: SMTP, o MAIL_BACKEND=log explícito para no enviar (desarrollo)
+	if err := mailer.Init(); err != nil {
+		log.Fatal("Error configurando el envío de emails: ", err)
+	}
+	// Primer admin: BOOTSTRAP_ADMIN_EMAIL recibe el rol admin al arrancar (si la cuenta existe)
+	if email := os.Getenv("BOOTSTRAP_ADMIN_EMAIL"); email != "" {
+		if promoted, err := repository.PromoteToAdmin(email); err != nil {
+			log.Println("⚠️ Error promoviendo a admin:", err)
+		} else if promoted {
+			log.Println("👑 Usuario promovido a admin:", email)
+		}
+	}
+	// Jobs programados (estados de eventos, resultados, notificaciones). Con varias réplicas el
+	// advisory lock de cada job evita que se ejecute más de una vez a la vez.
+	if os.Getenv("JOBS_ENABLED") != "false" {
+		interval := time.Duration(getEnvAsInt("JOBS_INTERVAL_SECONDS", 60)) * time.Second
+		completeAfter := time.Duration(getEnvAsInt("EVENT_COMPLETE_AFTER_HOURS", 24)) * time.Hour
+		scheduler := jobs.NewScheduler()
+		for _, job := range jobs.EventJobs(interval, completeAfter) {
+			scheduler.Add(job)
+		}
+		for _, job := range jobs.AuthJobs(time.Hour) {
+			scheduler.Add(job)
+		}
+		notifyInterval := time.Duration(getEnvAsInt("NOTIFICATIONS_INTERVAL_SECONDS", 15)) * time.Second
+		scheduler.Add(jobs.NotificationJob(notify.DefaultDispatcher(), notifyInterval))
+		scheduler.Add(jobs.WebhookJob(webhooks.NewSender(), notifyInterval))
+		scheduler.Start(context.Background())
+	}
+
 	// Iniciar servidor
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -42,44 +81,137 @@ func main() {
 	}
 
 	router := mux.NewRouter()
+	// Stream en vivo: va antes del subrouter /api porque EventSource no envía el header
+	// Authorization; StreamAuthMiddleware acepta ?stream_token= o el header
+	router.Handle("/api/events/{id}/live", middleware.StreamAuthMiddleware(middleware.RequireEventVisible(http.HandlerFunc(handlers.LiveEventHandler)))).Methods("GET")
+
 	api := router.PathPrefix("/api").Subrouter()
 	api.Use(middleware.AuthMiddleware) // protege todas las rutas /api/*
 
-	// Solo organizers pueden crear eventos
-	api.Handle("/events", middleware.RoleMiddleware("organizer")(http.HandlerFunc(handlers.CreateEvent))).Methods("POST")
-	// Editar evento (organizer dueño)
-	api.Handle("/events/{id}",middleware.RoleMiddleware("organizer")(http.HandlerFunc(handlers.UpdateEventHandler)),).Methods("PUT")
-	// Eliminar evento (organizer dueño)
-	api.Handle("/events/{id}",middleware.RoleMiddleware("organizer")(http.HandlerFunc(handlers.DeleteEventHandler)),).Methods("DELETE")
-	// Solo organizers pueden ver inscritos
-	api.Handle("/events/{id}/registrations", middleware.RoleMiddleware("organizer")(http.HandlerFunc(handlers.GetEventRegistrationsHandler))).Methods("GET")
-	// Cancelar evento (solo organizer dueño)
-	api.Handle("/events/{id}/cancel",middleware.RoleMiddleware("organizer")(http.HandlerFunc(handlers.CancelEventHandler)),	).Methods("POST")
+	// Crear eventos (organizers con el email verificado)
+	api.Handle("/events", middleware.RequirePermission(policy.EventCreate)(http.HandlerFunc(handlers.CreateEvent))).Methods("POST")
+	// Editar evento
+	api.Handle("/events/{id}",middleware.RequireEventPermission(policy.EventEdit)(http.HandlerFunc(handlers.UpdateEventHandler)),).Methods("PUT")
+	// Eliminar evento (solo el dueño o un admin)
+	api.Handle("/events/{id}",middleware.RequireEventPermission(policy.EventDelete)(http.HandlerFunc(handlers.DeleteEventHandler)),).Methods("DELETE")
+	// Ver inscritos
+	api.Handle("/events/{id}/registrations", middleware.RequireEventPermission(policy.RegistrationsRead)(http.HandlerFunc(handlers.GetEventRegistrationsHandler))).Methods("GET")
+	// Staff del evento
+	api.Handle("/events/{id}/staff", middleware.RequireEventPermission(policy.StaffManage)(http.HandlerFunc(handlers.AssignStaffHandler))).Methods("POST")
+	api.Handle("/events/{id}/staff", middleware.RequireEventPermission(policy.StaffManage)(http.HandlerFunc(handlers.GetEventStaffHandler))).Methods("GET")
+	api.Handle("/events/{id}/staff/{staffId}", middleware.RequireEventPermission(policy.StaffManage)(http.HandlerFunc(handlers.RemoveStaffHandler))).Methods("DELETE")
+	// Categorías / distancias del evento
+	api.Handle("/events/{id}/categories", middleware.RequireEventPermission(policy.EventEdit)(http.HandlerFunc(handlers.CreateCategoryHandler))).Methods("POST")
+	api.Handle("/events/{id}/categories/{categoryId}", middleware.RequireEventPermission(policy.EventEdit)(http.HandlerFunc(handlers.UpdateCategoryHandler))).Methods("PUT")
+	api.Handle("/events/{id}/categories/{categoryId}", middleware.RequireEventPermission(policy.EventEdit)(http.HandlerFunc(handlers.DeleteCategoryHandler))).Methods("DELETE")
+	// Reasignar dorsal
+	api.Handle("/events/{id}/registrations/{registrationId}/bib", middleware.RequireEventPermission(policy.RegistrationsManage)(http.HandlerFunc(handlers.SetRegistrationBibHandler))).Methods("PUT")
+	// Importar ruta GPX/KML
+	api.Handle("/events/{id}/route", middleware.RequireEventPermission(policy.EventEdit)(http.HandlerFunc(handlers.UploadEventRouteHandler))).Methods("POST")
+	// Cambiar estado del evento (publicar, abrir/cerrar inscripciones, iniciar, finalizar...)
+	api.Handle("/events/{id}/status", middleware.RequireEventPermission(policy.EventStatus)(http.HandlerFunc(handlers.TransitionEventStatusHandler))).Methods("POST")
+	api.Handle("/events/{id}/status-history", middleware.RequireEventPermission(policy.EventView)(http.HandlerFunc(handlers.GetEventStatusHistoryHandler))).Methods("GET")
+	// Estado de entrega de las notificaciones del evento
+	api.Handle("/events/{id}/notifications", middleware.RequireEventPermission(policy.NotificationsRead)(http.HandlerFunc(handlers.GetEventNotificationsHandler))).Methods("GET")
+	// Recordatorios previos al evento
+	api.Handle("/events/{id}/reminders", middleware.RequireEventPermission(policy.EventView)(http.HandlerFunc(handlers.GetEventRemindersHandler))).Methods("GET")
+	api.Handle("/events/{id}/reminders", middleware.RequireEventPermission(policy.EventEdit)(http.HandlerFunc(handlers.CreateEventReminderHandler))).Methods("POST")
+	api.Handle("/events/{id}/reminders/{reminderId}", middleware.RequireEventPermission(policy.EventEdit)(http.HandlerFunc(handlers.DeleteEventReminderHandler))).Methods("DELETE")
+	// Webhooks del organizer (por evento o para toda la cuenta) y su log de entregas
+	api.Handle("/webhooks", middleware.RequirePermission(policy.WebhooksManage)(http.HandlerFunc(handlers.CreateWebhookHandler))).Methods("POST")
+	api.Handle("/webhooks", middleware.RequirePermission(policy.WebhooksManage)(http.HandlerFunc(handlers.GetWebhooksHandler))).Methods("GET")
+	api.Handle("/webhooks/{id}", middleware.RequirePermission(policy.WebhooksManage)(http.HandlerFunc(handlers.DeleteWebhookHandler))).Methods("DELETE")
+	api.Handle("/webhooks/{id}/deliveries", middleware.RequirePermission(policy.WebhooksManage)(http.HandlerFunc(handlers.GetWebhookDeliveriesHandler))).Methods("GET")
+	api.Handle("/webhooks/{id}/deliveries/{deliveryId}/replay", middleware.RequirePermission(policy.WebhooksManage)(http.HandlerFunc(handlers.ReplayWebhookDeliveryHandler))).Methods("POST")
+	// Permisos concedidos por evento (solo el dueño o un admin)
+	api.Handle("/events/{id}/grants", middleware.RequireEventPermission(policy.GrantsManage)(http.HandlerFunc(handlers.GetEventGrantsHandler))).Methods("GET")
+	api.Handle("/events/{id}/grants", middleware.RequireEventPermission(policy.GrantsManage)(http.HandlerFunc(handlers.GrantEventPermissionHandler))).Methods("POST")
+	api.Handle("/events/{id}/grants/{grantId}", middleware.RequireEventPermission(policy.GrantsManage)(http.HandlerFunc(handlers.RevokeEventPermissionHandler))).Methods("DELETE")
+	// Equipo del evento: owner, coorganizadores, observadores y staff
+	api.Handle("/events/{id}/members", middleware.RequireEventPermission(policy.EventView)(http.HandlerFunc(handlers.GetEventMembersHandler))).Methods("GET")
+	api.Handle("/events/{id}/members/{userId}", middleware.RequireEventPermission(policy.MembersManage)(http.HandlerFunc(handlers.UpdateEventMemberHandler))).Methods("PUT")
+	api.Handle("/events/{id}/members/{userId}", middleware.RequireEventPermission(policy.MembersManage)(http.HandlerFunc(handlers.RemoveEventMemberHandler))).Methods("DELETE")
+	// Invitaciones al equipo por email
+	api.Handle("/events/{id}/invitations", middleware.RequireEventPermission(policy.MembersManage)(http.HandlerFunc(handlers.InviteEventMemberHandler))).Methods("POST")
+	api.Handle("/events/{id}/invitations", middleware.RequireEventPermission(policy.MembersManage)(http.HandlerFunc(handlers.GetEventInvitationsHandler))).Methods("GET")
+	api.Handle("/events/{id}/invitations/{invitationId}", middleware.RequireEventPermission(policy.MembersManage)(http.HandlerFunc(handlers.DeleteEventInvitationHandler))).Methods("DELETE")
+	api.Handle("/invitations/accept", middleware.RequireVerifiedEmail(http.HandlerFunc(handlers.AcceptEventInvitationHandler))).Methods("POST")
+	// Cancelar evento
+	api.Handle("/events/{id}/cancel",middleware.RequireEventPermission(policy.EventStatus)(http.HandlerFunc(handlers.CancelEventHandler)),	).Methods("POST")
 	
 	
 
+	// Administración de usuarios y solicitudes de organizador (solo admin)
+	api.Handle("/admin/users", middleware.RequirePermission(policy.UsersManage)(http.HandlerFunc(handlers.AdminListUsersHandler))).Methods("GET")
+	api.Handle("/admin/users/{id}", middleware.RequirePermission(policy.UsersManage)(http.HandlerFunc(handlers.AdminGetUserHandler))).Methods("GET")
+	api.Handle("/admin/users/{id}/role", middleware.RequirePermission(policy.UsersManage)(http.HandlerFunc(handlers.AdminSetUserRoleHandler))).Methods("PUT")
+	api.Handle("/admin/users/{id}/suspend", middleware.RequirePermission(policy.UsersManage)(http.HandlerFunc(handlers.AdminSuspendUserHandler))).Methods("POST")
+	api.Handle("/admin/users/{id}/reactivate", middleware.RequirePermission(policy.UsersManage)(http.HandlerFunc(handlers.AdminReactivateUserHandler))).Methods("POST")
+	api.Handle("/admin/organizer-applications", middleware.RequirePermission(policy.ApplicationsReview)(http.HandlerFunc(handlers.AdminListApplicationsHandler))).Methods("GET")
+	api.Handle("/admin/organizer-applications/{id}/approve", middleware.RequirePermission(policy.ApplicationsReview)(http.HandlerFunc(handlers.AdminApproveApplicationHandler))).Methods("POST")
+	api.Handle("/admin/organizer-applications/{id}/reject", middleware.RequirePermission(policy.ApplicationsReview)(http.HandlerFunc(handlers.AdminRejectApplicationHandler))).Methods("POST")
+
+	// Solicitud para ser organizador (runners con email verificado)
+	api.Handle("/organizer-applications", middleware.RequirePermission(policy.OrganizerApply)(http.HandlerFunc(handlers.CreateOrganizerApplicationHandler))).Methods("POST")
+	api.HandleFunc("/me/organizer-application", handlers.GetMyOrganizerApplicationHandler).Methods("GET")
+
 	// Todos los autenticados pueden ver eventos
 	api.HandleFunc("/events", handlers.GetEventsHandler).Methods("GET")
 	api.HandleFunc("/events/{id}", handlers.GetEventDetailHandler).Methods("GET")
-
-	// Solo runners pueden registrarse en eventos
-	api.Handle("/events/{id}/register", middleware.RoleMiddleware("runner")(http.HandlerFunc(handlers.RegisterEventHandler))).Methods("POST")
-	// Cancelar inscripción (solo runners)
-	api.Handle("/events/{id}/register",	middleware.RoleMiddleware("runner")(http.HandlerFunc(handlers.CancelRegistrationHandler)),).Methods("DELETE")
-	// Ver mis inscripciones (solo runners)
-	api.Handle("/my-registrations",	middleware.RoleMiddleware("runner")(http.HandlerFunc(handlers.GetMyRegistrationsHandler)),).Methods("GET")
-	// Check-in en checkpoint (solo runners inscritos)
-	api.Handle("/events/{id}/checkpoint/{checkpointId}",middleware.RoleMiddleware("runner")(http.HandlerFunc(handlers.CheckinHandler)),).Methods("POST")
+	api.Handle("/events/{id}/categories", middleware.RequireEventVisible(http.HandlerFunc(handlers.GetEventCategoriesHandler))).Methods("GET")
+
+	// Solo runners con el email verificado pueden registrarse en eventos
+	api.Handle("/events/{id}/register", middleware.RequirePermission(policy.EventRegister)(http.HandlerFunc(handlers.RegisterEventHandler))).Methods("POST")
+	// Cancelar inscripción
+	api.Handle("/events/{id}/register",	middleware.RequirePermission(policy.RegistrationCancel)(http.HandlerFunc(handlers.CancelRegistrationHandler)),).Methods("DELETE")
+	// Ver mis inscripciones
+	api.Handle("/my-registrations",	middleware.RequirePermission(policy.RegistrationsReadOwn)(http.HandlerFunc(handlers.GetMyRegistrationsHandler)),).Methods("GET")
+	// Check-in en checkpoint (el handler valida la inscripción)
+	api.Handle("/events/{id}/checkpoint/{checkpointId}",middleware.RequirePermission(policy.CheckinSelf)(http.HandlerFunc(handlers.CheckinHandler)),).Methods("POST")
+
+	// Resultados del evento (cualquier autenticado; los borradores solo el equipo)
+	api.Handle("/events/{id}/results", middleware.RequireEventVisible(http.HandlerFunc(handlers.GetEventResultsHandler))).Methods("GET")
+	// Podios por género y grupo de edad
+	api.Handle("/events/{id}/results/age-groups", middleware.RequireEventVisible(http.HandlerFunc(handlers.GetAgeGroupResultsHandler))).Methods("GET")
+	// Seguimiento en vivo de checkins (SSE): el token de stream se pide con el access token
+	// y el stream se abre con ?stream_token= (ver la ruta registrada fuera de /api)
+	api.Handle("/events/{id}/live/token", middleware.RequireEventVisible(http.HandlerFunc(handlers.LiveTokenHandler))).Methods("POST")
+
+	// Checkin registrado por staff en nombre de un corredor (el handler valida el checkpoint)
+	api.HandleFunc("/events/{id}/checkpoint/{checkpointId}/staff-checkin", handlers.StaffCheckinHandler).Methods("POST")
+
+	// Búsqueda de dorsales (organizer, staff o con permiso concedido)
+	api.Handle("/events/{id}/bibs", middleware.RequireEventPermission(policy.BibsRead)(http.HandlerFunc(handlers.GetEventBibsHandler))).Methods("GET")
+	api.Handle("/events/{id}/bibs/{bib}", middleware.RequireEventPermission(policy.BibsRead)(http.HandlerFunc(handlers.GetBibHandler))).Methods("GET")
 
 	// Cualquier usuario autenticado puede ver su propio perfil
 	api.HandleFunc("/me", handlers.GetMeHandler).Methods("GET")
+	api.HandleFunc("/me", handlers.UpdateMeHandler).Methods("PUT")
+	api.HandleFunc("/me/resend-verification", handlers.ResendVerificationHandler).Methods("POST")
+	// Sesiones abiertas del usuario
+	api.HandleFunc("/me/sessions", handlers.GetSessionsHandler).Methods("GET")
+	api.HandleFunc("/me/sessions/{id}", handlers.RevokeSessionHandler).Methods("DELETE")
+	api.HandleFunc("/auth/logout-all", handlers.LogoutAllHandler).Methods("POST")
+	// Preferencias de notificación y bandeja de entrada del usuario
+	api.HandleFunc("/me/notification-preferences", handlers.GetNotificationPreferencesHandler).Methods("GET")
+	api.HandleFunc("/me/notification-preferences", handlers.UpdateNotificationPreferencesHandler).Methods("PUT")
+	api.HandleFunc("/notifications", handlers.GetInboxHandler).Methods("GET")
+	api.HandleFunc("/notifications/read-all", handlers.MarkAllInboxReadHandler).Methods("POST")
+	api.HandleFunc("/notifications/{id}/read", handlers.MarkInboxReadHandler).Methods("POST")
 	// Obtener eventos creados por los usuarios autentificados
-	api.Handle("/events/{id}/route", (http.HandlerFunc(handlers.GetEventRouteHandler)),).Methods("GET")
+	api.Handle("/events/{id}/route", middleware.RequireEventVisible(http.HandlerFunc(handlers.GetEventRouteHandler))).Methods("GET")
 
 
 	// Rutas públicas
+	// Claves públicas para verificar los access tokens (apps y servicios asociados)
+	router.HandleFunc("/.well-known/jwks.json", handlers.JWKSHandler).Methods("GET")
 	router.HandleFunc("/auth/register", handlers.RegisterHandler).Methods("POST")
 	router.HandleFunc("/auth/login", handlers.LoginHandler).Methods("POST")
+	router.HandleFunc("/auth/refresh", handlers.RefreshTokenHandler).Methods("POST")
+	router.HandleFunc("/auth/logout", handlers.LogoutHandler).Methods("POST")
+	router.HandleFunc("/auth/forgot-password", handlers.ForgotPasswordHandler).Methods("POST")
+	router.HandleFunc("/auth/reset-password", handlers.ResetPasswordHandler).Methods("POST")
+	router.HandleFunc("/auth/verify-email", handlers.VerifyEmailHandler).Methods("POST")
 
 
 	// Configurar CORS
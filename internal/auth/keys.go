@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Key es una clave de firma/verificación de access tokens identificada por su kid
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	private interface{} // nil = solo verificación (clave retirada)
+	public  interface{}
+}
+
+// KeyManager firma con la clave activa y verifica con cualquiera de las cargadas, lo que
+// permite rotar: se publica la clave nueva, se cambia JWT_SIGNING_KID y la anterior se
+// mantiene hasta que venzan los tokens que firmó.
+type KeyManager struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+// keys es el KeyManager de la aplicación (ver InitKeys)
+var keys *KeyManager
+
+// InitKeys carga las claves según el entorno:
+//
+//	JWT_KEYS_DIR     directorio con claves PEM; el kid es el nombre del archivo sin ".pem".
+//	                 Claves privadas RSA (RS256, mínimo 2048 bits) o Ed25519 (EdDSA);
+//	                 las claves públicas solo sirven para verificar.
+//	JWT_SIGNING_KID  kid de la clave con la que se firma (por defecto, el último por orden alfabético)
+//
+// Sin JWT_KEYS_DIR se usa HS256 con JWT_SECRET (modo heredado, sin JWKS).
+//
+// Ejemplo: openssl genpkey -algorithm ed25519 -out keys/2026-10.pem
+func InitKeys() error {
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			return errors.New("configura JWT_KEYS_DIR (o JWT_SECRET en modo heredado)")
+		}
+		log.Println("⚠️ JWT_KEYS_DIR no configurado: los tokens se firman con HS256 y no se publica JWKS")
+		k := &Key{ID: "hs256", Method: jwt.SigningMethodHS256, private: []byte(secret), public: []byte(secret)}
+		keys = &KeyManager{signing: k, keys: map[string]*Key{k.ID: k}}
+		return nil
+	}
+
+	km, err := LoadKeyDir(dir, os.Getenv("JWT_SIGNING_KID"))
+	if err != nil {
+		return err
+	}
+	keys = km
+	log.Printf("🔑 Claves JWT cargadas: %d (firma con kid %q, %s)", len(km.keys), km.signing.ID, km.signing.Method.Alg())
+	return nil
+}
+
+// LoadKeyDir lee todas las claves *.pem del directorio
+func LoadKeyDir(dir, signingKID string) (*KeyManager, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	km := &KeyManager{keys: map[string]*Key{}}
+	for _, f := range files {
+		kid := strings.TrimSuffix(filepath.Base(f), ".pem")
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		k, err := parseKey(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("clave %s: %w", f, err)
+		}
+		km.keys[kid] = k
+		if k.private != nil && signingKID == "" {
+			km.signing = k // el último por orden alfabético
+		}
+	}
+	if signingKID != "" {
+		km.signing = km.keys[signingKID]
+	}
+	if km.signing == nil || km.signing.private == nil {
+		return nil, fmt.Errorf("no hay clave privada para firmar en %s (JWT_SIGNING_KID=%q)", dir, signingKID)
+	}
+	return km, nil
+}
+
+func parseKey(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no es un PEM válido")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("tipo PEM no soportado: %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return nil, errors.New("las claves RSA deben tener al menos 2048 bits")
+		}
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, private: k, public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, public: k}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: kid, Method: SigningMethodEdDSA, private: k, public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: kid, Method: SigningMethodEdDSA, public: k}, nil
+	}
+	return nil, errors.New("solo se admiten claves RSA o Ed25519")
+}
+
+// Sign firma las claims con la clave activa y pone su kid en la cabecera
+func (km *KeyManager) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(km.signing.Method, claims)
+	token.Header["kid"] = km.signing.ID
+	return token.SignedString(km.signing.private)
+}
+
+// Keyfunc elige la clave por kid y exige que el algoritmo sea el de esa clave
+// (evita la confusión de algoritmos, p.ej. HS256 firmado con una clave pública)
+func (km *KeyManager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" && km.signing.ID == "hs256" {
+		kid = "hs256" // tokens heredados sin kid
+	}
+	k, ok := km.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("kid desconocido: %q", kid)
+	}
+	if token.Method.Alg() != k.Method.Alg() {
+		return nil, ErrInvalidToken
+	}
+	return k.public, nil
+}
+
+// JWK es una clave pública en formato JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`   // RSA
+	E   string `json:"e,omitempty"`   // RSA
+	Crv string `json:"crv,omitempty"` // OKP
+	X   string `json:"x,omitempty"`   // OKP
+}
+
+// JWKS devuelve las claves públicas de verificación (las simétricas nunca se publican)
+func (km *KeyManager) JWKS() []JWK {
+	out := []JWK{}
+	ids := make([]string, 0, len(km.keys))
+	for id := range km.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	b64 := base64.RawURLEncoding.EncodeToString
+	for _, id := range ids {
+		k := km.keys[id]
+		switch pub := k.public.(type) {
+		case *rsa.PublicKey:
+			out = append(out, JWK{Kty: "RSA", Kid: id, Alg: k.Method.Alg(), Use: "sig",
+				N: b64(pub.N.Bytes()), E: b64(big.NewInt(int64(pub.E)).Bytes())})
+		case ed25519.PublicKey:
+			out = append(out, JWK{Kty: "OKP", Kid: id, Alg: k.Method.Alg(), Use: "sig", Crv: "Ed25519", X: b64(pub)})
+		}
+	}
+	return out
+}
+
+// PublicJWKS devuelve el JWKS del KeyManager de la aplicación
+func PublicJWKS() []JWK {
+	if keys == nil {
+		return []JWK{}
+	}
+	return keys.JWKS()
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+)
+
+// TokenPair es la respuesta de login y refresh
+type TokenPair struct {
+	AccessToken  string    `json:"token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token"`
+	SessionID    int       `json:"session_id"`
+}
+
+// StartSession crea la sesión del login y emite el primer par de tokens
+func StartSession(user models.User, userAgent, ip string) (TokenPair, error) {
+	refresh, err := NewOpaqueToken()
+	if err != nil {
+		return TokenPair{}, err
+	}
+	sessionID, err := repository.CreateSession(user.ID, HashToken(refresh), time.Now().Add(RefreshTokenTTL()), userAgent, ip)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return issuePair(user, sessionID, refresh)
+}
+
+// Refresh rota el refresh token y emite un access token nuevo para la misma sesión
+func Refresh(refreshToken, ip string) (TokenPair, error) {
+	next, err := NewOpaqueToken()
+	if err != nil {
+		return TokenPair{}, err
+	}
+	session, err := repository.RotateSession(HashToken(refreshToken), HashToken(next), ip)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	user, err := repository.GetUserByID(session.UserID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if user.SuspendedAt != nil {
+		return TokenPair{}, repository.ErrUserSuspended
+	}
+	return issuePair(user, session.ID, next)
+}
+
+// Logout revoca la sesión del refresh token. Un token desconocido no es un error.
+func Logout(refreshToken string) error {
+	_, err := repository.RevokeSessionByToken(HashToken(refreshToken))
+	return err
+}
+
+// IsInvalidRefresh indica si el error de Refresh se debe a un token no válido (401)
+func IsInvalidRefresh(err error) bool {
+	return errors.Is(err, repository.ErrSessionNotFound) || errors.Is(err, repository.ErrRefreshTokenReused) ||
+		errors.Is(err, repository.ErrUserSuspended)
+}
+
+func issuePair(user models.User, sessionID int, refresh string) (TokenPair, error) {
+	access, expires, err := IssueAccessToken(user.ID, user.Email, user.Role, sessionID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return TokenPair{AccessToken: access, ExpiresAt: expires, RefreshToken: refresh, SessionID: sessionID}, nil
+}
@@ -0,0 +1,156 @@
+// Package auth emite y valida los tokens de acceso (JWT) y maneja las sesiones de login
+// con refresh tokens rotativos.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Claims del access token. SessionID ("sid") enlaza el token con su sesión para poder revocarlo.
+type Claims struct {
+	UserID    int    `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID int    `json:"sid"`
+	jwt.StandardClaims
+}
+
+// StreamClaims del token de stream: solo sirve para abrir el stream en vivo de un evento
+// (EventSource no puede enviar el header Authorization, así que viaja en la URL)
+type StreamClaims struct {
+	UserID    int `json:"user_id"`
+	SessionID int `json:"sid"`
+	EventID   int `json:"event_id"`
+	jwt.StandardClaims
+}
+
+// streamAudience distingue los tokens de stream de los access tokens
+const streamAudience = "live"
+
+var (
+	ErrInvalidToken  = errors.New("token inválido")
+	ErrKeysNotLoaded = errors.New("claves JWT no inicializadas")
+)
+
+// AccessTokenTTL: ACCESS_TOKEN_TTL_MINUTES (por defecto 15)
+func AccessTokenTTL() time.Duration {
+	return time.Duration(envInt("ACCESS_TOKEN_TTL_MINUTES", 15)) * time.Minute
+}
+
+// RefreshTokenTTL: REFRESH_TOKEN_TTL_DAYS (por defecto 30)
+func RefreshTokenTTL() time.Duration {
+	return time.Duration(envInt("REFRESH_TOKEN_TTL_DAYS", 30)) * 24 * time.Hour
+}
+
+// StreamTokenTTL: STREAM_TOKEN_TTL_SECONDS (por defecto 60). Solo tiene que durar hasta
+// abrir la conexión; las reconexiones piden un token nuevo.
+func StreamTokenTTL() time.Duration {
+	return time.Duration(envInt("STREAM_TOKEN_TTL_SECONDS", 60)) * time.Second
+}
+
+// IssueAccessToken firma un JWT de corta duración para la sesión con la clave activa
+func IssueAccessToken(userID int, email, role string, sessionID int) (string, time.Time, error) {
+	now := time.Now()
+	expires := now.Add(AccessTokenTTL())
+	claims := &Claims{
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expires.Unix(),
+			IssuedAt:  now.Unix(),
+		},
+	}
+	if keys == nil {
+		return "", expires, ErrKeysNotLoaded
+	}
+	signed, err := keys.Sign(claims)
+	return signed, expires, err
+}
+
+// ParseAccessToken valida firma y vencimiento. No comprueba si la sesión sigue activa.
+func ParseAccessToken(tokenStr string) (*Claims, error) {
+	if keys == nil {
+		return nil, ErrKeysNotLoaded
+	}
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, keys.Keyfunc)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid || claims.Audience != "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// IssueStreamToken firma un token de corta duración para el stream en vivo del evento
+func IssueStreamToken(userID, sessionID, eventID int) (string, time.Time, error) {
+	now := time.Now()
+	expires := now.Add(StreamTokenTTL())
+	claims := &StreamClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		EventID:   eventID,
+		StandardClaims: jwt.StandardClaims{
+			Audience:  streamAudience,
+			ExpiresAt: expires.Unix(),
+			IssuedAt:  now.Unix(),
+		},
+	}
+	if keys == nil {
+		return "", expires, ErrKeysNotLoaded
+	}
+	signed, err := keys.Sign(claims)
+	return signed, expires, err
+}
+
+// ParseStreamToken valida firma, vencimiento y que el token sea de stream para ese evento.
+// No comprueba si la sesión sigue activa.
+func ParseStreamToken(tokenStr string, eventID int) (*StreamClaims, error) {
+	if keys == nil {
+		return nil, ErrKeysNotLoaded
+	}
+	claims := &StreamClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, keys.Keyfunc)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid || claims.Audience != streamAudience || claims.EventID != eventID {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// HashToken es el SHA-256 en hex con el que se guardan los tokens opacos
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewOpaqueToken genera un token aleatorio de 256 bits apto para URLs
+func NewOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func envInt(name string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || v <= 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,73 @@
+package geo
+
+import "math"
+
+// Haversine formula para distancia en metros
+func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	const R = 6371000
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLon := (lon2 - lon1) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*
+			math.Sin(dLon/2)*math.Sin(dLon/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+	return R * c
+}
+
+// Point es una coordenada geográfica en grados
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// CumulativeDistances devuelve la distancia acumulada (metros) hasta cada punto del trazado
+func CumulativeDistances(pts []Point) []float64 {
+	cum := make([]float64, len(pts))
+	for i := 1; i < len(pts); i++ {
+		cum[i] = cum[i-1] + Haversine(pts[i-1].Lat, pts[i-1].Lng, pts[i].Lat, pts[i].Lng)
+	}
+	return cum
+}
+
+// DistanceAlong proyecta p sobre el trazado y devuelve la distancia recorrida (metros)
+// hasta la proyección. Solo considera posiciones a partir de minAlong, para que en
+// recorridos de ida y vuelta cada checkpoint caiga en el tramo correcto.
+func DistanceAlong(pts []Point, cum []float64, p Point, minAlong float64) float64 {
+	if len(pts) < 2 {
+		return 0
+	}
+
+	best, bestAlong := math.Inf(1), minAlong
+	for i := 0; i < len(pts)-1; i++ {
+		segLen := cum[i+1] - cum[i]
+		if cum[i+1] < minAlong {
+			continue
+		}
+		t := projectOnSegment(p, pts[i], pts[i+1])
+		if segLen > 0 && cum[i]+t*segLen < minAlong {
+			t = (minAlong - cum[i]) / segLen
+		}
+		proj := Point{
+			Lat: pts[i].Lat + t*(pts[i+1].Lat-pts[i].Lat),
+			Lng: pts[i].Lng + t*(pts[i+1].Lng-pts[i].Lng),
+		}
+		if d := Haversine(p.Lat, p.Lng, proj.Lat, proj.Lng); d < best {
+			best, bestAlong = d, cum[i]+t*segLen
+		}
+	}
+	return bestAlong
+}
+
+// projectOnSegment devuelve t ∈ [0,1] de la proyección de p sobre a→b, usando una
+// aproximación equirectangular local (suficiente para tramos de unos pocos km).
+func projectOnSegment(p, a, b Point) float64 {
+	cosLat := math.Cos(a.Lat * math.Pi / 180)
+	bx, by := (b.Lng-a.Lng)*cosLat, b.Lat-a.Lat
+	px, py := (p.Lng-a.Lng)*cosLat, p.Lat-a.Lat
+	lenSq := bx*bx + by*by
+	if lenSq == 0 {
+		return 0
+	}
+	t := (px*bx + py*by) / lenSq
+	return math.Max(0, math.Min(1, t))
+}
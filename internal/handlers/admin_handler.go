@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"sport-events-backend/internal/middleware"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+)
+
+// GET /api/admin/users?q=ana&role=organizer&suspended=false&limit=50&offset=0  (solo admin)
+func AdminListUsersHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := models.UserFilter{
+		Query: strings.TrimSpace(q.Get("q")),
+		Role:  q.Get("role"),
+		Limit: 50,
+	}
+	if f.Role != "" && !models.ValidRole(f.Role) {
+		http.Error(w, "role inválido", http.StatusBadRequest)
+		return
+	}
+	if v := q.Get("suspended"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "suspended debe ser true o false", http.StatusBadRequest)
+			return
+		}
+		f.Suspended = &b
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 200 {
+			http.Error(w, "limit debe estar entre 1 y 200", http.StatusBadRequest)
+			return
+		}
+		f.Limit = n
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, "offset inválido", http.StatusBadRequest)
+			return
+		}
+		f.Offset = n
+	}
+
+	users, total, err := repository.SearchUsers(f)
+	if err != nil {
+		http.Error(w, "Error obteniendo usuarios: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"total":  total,
+		"limit":  f.Limit,
+		"offset": f.Offset,
+		"users":  users,
+	})
+}
+
+// GET /api/admin/users/{id}  (solo admin)
+func AdminGetUserHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de usuario inválido", http.StatusBadRequest)
+		return
+	}
+
+	user, err := repository.GetUserByID(userID)
+	if err != nil {
+		http.Error(w, "Usuario no encontrado", http.StatusNotFound)
+		return
+	}
+	resp := map[string]interface{}{"user": user}
+	if app, err := repository.GetLatestOrganizerApplication(userID); err == nil {
+		resp["organizer_application"] = app
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// PUT /api/admin/users/{id}/role  (solo admin)
+// Body: {"role": "organizer"}
+func AdminSetUserRoleHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminTargetUser(w, r)
+	if !ok {
+		return
+	}
+
+	var in struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if !models.ValidRole(in.Role) {
+		http.Error(w, "role debe ser runner, organizer o admin", http.StatusBadRequest)
+		return
+	}
+
+	if err := repository.SetUserRole(userID, in.Role); err != nil {
+		writeAdminUserError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"id": userID, "role": in.Role})
+}
+
+// POST /api/admin/users/{id}/suspend  (solo admin)
+// Body opcional: {"reason": "..."}. Cierra todas las sesiones del usuario.
+func AdminSuspendUserHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminTargetUser(w, r)
+	if !ok {
+		return
+	}
+
+	var in struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "JSON inválido", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := repository.SuspendUser(userID, strings.TrimSpace(in.Reason)); err != nil {
+		writeAdminUserError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Cuenta suspendida"})
+}
+
+// POST /api/admin/users/{id}/reactivate  (solo admin)
+func AdminReactivateUserHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminTargetUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := repository.ReactivateUser(userID); err != nil {
+		writeAdminUserError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Cuenta reactivada"})
+}
+
+// GET /api/admin/organizer-applications?status=pending  (solo admin)
+func AdminListApplicationsHandler(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.ApplicationPending, models.ApplicationApproved, models.ApplicationRejected:
+	default:
+		http.Error(w, "status inválido", http.StatusBadRequest)
+		return
+	}
+
+	apps, err := repository.GetOrganizerApplications(status)
+	if err != nil {
+		http.Error(w, "Error obteniendo solicitudes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(apps)
+}
+
+// POST /api/admin/organizer-applications/{id}/approve  (solo admin)
+func AdminApproveApplicationHandler(w http.ResponseWriter, r *http.Request) {
+	reviewApplication(w, r, true)
+}
+
+// POST /api/admin/organizer-applications/{id}/reject  (solo admin)
+func AdminRejectApplicationHandler(w http.ResponseWriter, r *http.Request) {
+	reviewApplication(w, r, false)
+}
+
+// reviewApplication resuelve la solicitud; body opcional: {"note": "..."}
+func reviewApplication(w http.ResponseWriter, r *http.Request, approve bool) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	appID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de solicitud inválido", http.StatusBadRequest)
+		return
+	}
+	var in struct {
+		Note string `json:"note"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, "JSON inválido", http.StatusBadRequest)
+			return
+		}
+	}
+
+	app, err := repository.ReviewOrganizerApplication(appID, claims.UserID, approve, strings.TrimSpace(in.Note))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrApplicationNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, repository.ErrApplicationReviewed):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Error revisando la solicitud: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(app)
+}
+
+// adminTargetUser lee {id} y evita que un admin se cambie el rol o se suspenda a sí mismo
+func adminTargetUser(w http.ResponseWriter, r *http.Request) (int, bool) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, false
+	}
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de usuario inválido", http.StatusBadRequest)
+		return 0, false
+	}
+	if userID == claims.UserID {
+		http.Error(w, "No puedes modificar tu propia cuenta desde el panel de administración", http.StatusConflict)
+		return 0, false
+	}
+	return userID, true
+}
+
+func writeAdminUserError(w http.ResponseWriter, err error) {
+	if errors.Is(err, repository.ErrUserNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, "Error actualizando usuario: "+err.Error(), http.StatusInternalServerError)
+}
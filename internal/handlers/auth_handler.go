@@ -2,13 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
-	"time"
 	"strconv"
-	"log"
 
-	"github.com/dgrijalva/jwt-go"
+	"sport-events-backend/internal/auth"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
 	"sport-events-backend/internal/services"
 )
 // getEnvAsInt obtiene una variable de entorno como entero
@@ -21,17 +22,6 @@ func getEnvAsInt(name string, defaultVal int) int {
 }
 	
 
-// Avoid reading JWT_SECRET at import time; read at runtime when creating tokens.
-// jwtKey is intentionally not a package-level variable to ensure changes to the
-// environment (or loading order) are respected.
-
-type Claims struct {
-	UserID int    `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
-	jwt.StandardClaims
-}
-
 func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 	var input struct {
 		Name     string `json:"name"`
@@ -45,14 +35,29 @@ func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := services.RegisterUser(input.Name, input.Email, input.Password, input.Role)
+	// Las cuentas se crean como runner; "organizer" deja una solicitud pendiente para un admin
+	if input.Role != "" && input.Role != models.RoleRunner && input.Role != models.RoleOrganizer {
+		http.Error(w, "role debe ser runner u organizer", http.StatusBadRequest)
+		return
+	}
+
+	user, err := services.RegisterUser(input.Name, input.Email, input.Password)
 	if err != nil {
 		http.Error(w, "Error registrando usuario: "+err.Error(), 500)
 		return
 	}
 
+	message := "Usuario creado; revisa tu email para verificar la cuenta"
+	if input.Role == models.RoleOrganizer {
+		if _, err := repository.CreateOrganizerApplication(user.ID, "", ""); err != nil {
+			http.Error(w, "Error creando la solicitud de organizador: "+err.Error(), 500)
+			return
+		}
+		message += ". Tu solicitud para ser organizador quedó pendiente de aprobación"
+	}
+
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Usuario creado"})
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
 }
 
 func LoginHandler(w http.ResponseWriter, r *http.Request) {
@@ -67,42 +72,31 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user, err := services.AuthenticateUser(input.Email, input.Password)
+	if errors.Is(err, repository.ErrUserSuspended) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Credenciales inválidas", 401)
 		return
 	}
 
-	// crear token JWT
-	expiration := time.Now().Add(24 * time.Hour)
-	claims := &Claims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Role:   user.Role,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expiration.Unix(),
-		},
-	}
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		log.Println("⚠️ JWT_SECRET authn no está seteado en auth handler")
-	} else {
-		log.Println("🔑 JWT_SECRET cargado en auth handler: <redacted>")
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	// Use the runtime secret to sign the token so it matches validation in middleware.
-	tokenStr, err := token.SignedString([]byte(secret))
+	// crear la sesión: access token corto + refresh token rotativo
+	pair, err := auth.StartSession(user, r.UserAgent(), clientIP(r))
 	if err != nil {
 		http.Error(w, "Error creando token", 500)
 		return
 	}
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
-	"token": tokenStr,
+	"token":         pair.AccessToken,
+	"expires_at":    pair.ExpiresAt,
+	"refresh_token": pair.RefreshToken,
 	"user": map[string]interface{}{
 		"id":    user.ID,
 		"email": user.Email,
 		"role":  user.Role,
+		"email_verified": user.EmailVerifiedAt != nil,
 	},
 })
 
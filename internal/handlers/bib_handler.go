@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+	"sport-events-backend/internal/repository"
+)
+
+// PUT /api/events/{id}/registrations/{registrationId}/bib  (permiso registrations:manage)
+// Body: {"bib_number": 123} o {"bib_number": null} para quitarlo
+func SetRegistrationBibHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+	registrationID, err := strconv.Atoi(vars["registrationId"])
+	if err != nil {
+		http.Error(w, "ID de inscripción inválido", http.StatusBadRequest)
+		return
+	}
+
+	var in struct {
+		BibNumber *int `json:"bib_number"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if in.BibNumber != nil && *in.BibNumber < 1 {
+		http.Error(w, "bib_number debe ser mayor que 0", http.StatusBadRequest)
+		return
+	}
+
+	if err := repository.SetRegistrationBib(eventID, registrationID, in.BibNumber); err != nil {
+		if errors.Is(err, repository.ErrRegistrationNotFound) {
+			http.Error(w, "Inscripción no encontrada", http.StatusNotFound)
+			return
+		}
+		// Dorsal duplicado por UNIQUE (event_id, bib_number)
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			http.Error(w, "Ese dorsal ya está asignado en este evento", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Error asignando dorsal: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"registration_id": registrationID,
+		"bib_number":      in.BibNumber,
+	})
+}
+
+// GET /api/events/{id}/bibs  (permiso bibs:read: organizer dueño o staff)
+func GetEventBibsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+
+	bibs, err := repository.GetEventBibs(eventID)
+	if err != nil {
+		http.Error(w, "Error obteniendo dorsales: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bibs)
+}
+
+// GET /api/events/{id}/bibs/{bib}  (permiso bibs:read)
+func GetBibHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+	bib, err := strconv.Atoi(vars["bib"])
+	if err != nil {
+		http.Error(w, "Dorsal inválido", http.StatusBadRequest)
+		return
+	}
+
+	reg, err := repository.GetRegistrationByBib(eventID, bib)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Dorsal no encontrado en este evento", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error buscando dorsal: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reg)
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+	"sport-events-backend/internal/services"
+)
+
+// categoryInput es el body de alta/edición de una categoría
+type categoryInput struct {
+	Name          string        `json:"name"`
+	DistanceM     *float64      `json:"distance_m"` // si no viene y hay ruta, se calcula
+	Route         *models.Route `json:"route"`      // nil = usa la ruta del evento
+	Capacity      *int          `json:"capacity"`
+	StartTime     *time.Time    `json:"start_time"`
+	Price         *float64      `json:"price"`
+	BibRangeStart *int          `json:"bib_range_start"`
+	BibRangeEnd   *int          `json:"bib_range_end"`
+}
+
+// toCategory valida el input y lo convierte; si falla escribe la respuesta de error
+func (in categoryInput) toCategory(w http.ResponseWriter, eventID int) (models.EventCategory, bool) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		http.Error(w, "name es obligatorio", http.StatusBadRequest)
+		return models.EventCategory{}, false
+	}
+	if in.DistanceM != nil && *in.DistanceM <= 0 {
+		http.Error(w, "distance_m debe ser mayor que 0", http.StatusBadRequest)
+		return models.EventCategory{}, false
+	}
+	if in.Price != nil && *in.Price < 0 {
+		http.Error(w, "price no puede ser negativo", http.StatusBadRequest)
+		return models.EventCategory{}, false
+	}
+	if err := validateEventCapacity(in.Capacity); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return models.EventCategory{}, false
+	}
+	if err := validateBibRange(in.BibRangeStart, in.BibRangeEnd); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return models.EventCategory{}, false
+	}
+	if errs := validateRoute(in.Route); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return models.EventCategory{}, false
+	}
+
+	// Calcula también la distancia de cada checkpoint de la ruta de la categoría
+	metrics := services.ApplyRouteMetrics(in.Route)
+	distance := in.DistanceM
+	if distance == nil {
+		distance = metrics.DistanceM
+	}
+
+	return models.EventCategory{
+		EventID:       eventID,
+		Name:          name,
+		DistanceM:     distance,
+		Route:         in.Route,
+		Capacity:      in.Capacity,
+		StartTime:     in.StartTime,
+		Price:         in.Price,
+		BibRangeStart: in.BibRangeStart,
+		BibRangeEnd:   in.BibRangeEnd,
+	}, true
+}
+
+// POST /api/events/{id}/categories  (permiso event:edit)
+func CreateCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+	if !requireEditable(w, eventID) {
+		return
+	}
+
+	var in categoryInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	cat, ok := in.toCategory(w, eventID)
+	if !ok {
+		return
+	}
+
+	id, err := repository.CreateCategory(cat)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			http.Error(w, "Ya existe una categoría con ese nombre", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Error creando categoría: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	created, _ := repository.GetCategory(eventID, id)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// GET /api/events/{id}/categories  (cualquier autenticado; los borradores solo el equipo)
+func GetEventCategoriesHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+	if _, err := repository.GetEventStatus(eventID); err != nil {
+		http.Error(w, "Evento no encontrado", http.StatusNotFound)
+		return
+	}
+
+	cats, err := repository.GetEventCategories(eventID)
+	if err != nil {
+		http.Error(w, "Error obteniendo categorías: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cats)
+}
+
+// PUT /api/events/{id}/categories/{categoryId}  (permiso event:edit)
+func UpdateCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+	categoryID, err := strconv.Atoi(vars["categoryId"])
+	if err != nil {
+		http.Error(w, "ID de categoría inválido", http.StatusBadRequest)
+		return
+	}
+	if !requireEditable(w, eventID) {
+		return
+	}
+
+	var in categoryInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	cat, ok := in.toCategory(w, eventID)
+	if !ok {
+		return
+	}
+	cat.ID = categoryID
+	if !requireStableCategoryCheckpoints(w, eventID, categoryID, cat.Route) {
+		return
+	}
+
+	if err := repository.UpdateCategory(cat); err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			http.Error(w, "Categoría no encontrada", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, repository.ErrEventNotFound) {
+			http.Error(w, "Evento no encontrado", http.StatusNotFound)
+			return
+		}
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			http.Error(w, "Ya existe una categoría con ese nombre", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Error actualizando categoría: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	updated, _ := repository.GetCategory(eventID, categoryID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DELETE /api/events/{id}/categories/{categoryId}  (permiso event:edit, sin inscritos)
+func DeleteCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+	categoryID, err := strconv.Atoi(vars["categoryId"])
+	if err != nil {
+		http.Error(w, "ID de categoría inválido", http.StatusBadRequest)
+		return
+	}
+	if !requireEditable(w, eventID) {
+		return
+	}
+
+	if err := repository.DeleteCategory(eventID, categoryID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCategoryNotFound), errors.Is(err, repository.ErrEventNotFound):
+			http.Error(w, "Categoría no encontrada", http.StatusNotFound)
+		case errors.Is(err, repository.ErrCategoryHasRegistrations):
+			http.Error(w, "No se puede eliminar: la categoría tiene inscripciones", http.StatusConflict)
+		default:
+			http.Error(w, "Error eliminando categoría: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Categoría eliminada"})
+}
@@ -2,35 +2,46 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"math"
 	"net/http"
 	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+	"sport-events-backend/internal/geo"
+	"sport-events-backend/internal/lifecycle"
 	"sport-events-backend/internal/middleware"
+	"sport-events-backend/internal/models"
 	"sport-events-backend/internal/repository"
+	"sport-events-backend/internal/services"
 )
 
-const checkpointRadius = 3.0 // metros
-
-// Haversine formula para distancia en metros
-func haversine(lat1, lon1, lat2, lon2 float64) float64 {
-	const R = 6371000
-	dLat := (lat2 - lat1) * math.Pi / 180
-	dLon := (lon2 - lon1) * math.Pi / 180
-	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
-		math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*
-			math.Sin(dLon/2)*math.Sin(dLon/2)
-	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-	return R * c
-}
+// Valores por defecto si no se configuran CHECKPOINT_RADIUS_M / CHECKPOINT_MAX_ACCURACY_M
+const (
+	defaultCheckpointRadius = 30 // metros
+	defaultMaxAccuracy      = 50 // metros de tolerancia extra máxima por precisión GPS
+)
+
+// checkinTolerance calcula el radio del checkpoint (propio → evento → global) y la
+// distancia máxima permitida sumando la precisión reportada, con tope configurable.
+func checkinTolerance(cp models.Checkpoint, eventRadius *float64, accuracy *float64) (radius, allowed float64) {
+	radius = float64(getEnvAsInt("CHECKPOINT_RADIUS_M", defaultCheckpointRadius))
+	if eventRadius != nil && *eventRadius > 0 {
+		radius = *eventRadius
+	}
+	if cp.RadiusM > 0 {
+		radius = cp.RadiusM
+	}
 
-type Checkpoint struct {
-	ID   int     `json:"id"`
-	Name string  `json:"name"`
-	Lat  float64 `json:"lat"`
-	Lng  float64 `json:"lng"`
-	Type string  `json:"type"`
+	allowed = radius
+	if accuracy != nil {
+		maxAccuracy := float64(getEnvAsInt("CHECKPOINT_MAX_ACCURACY_M", defaultMaxAccuracy))
+		allowed += math.Min(*accuracy, maxAccuracy)
+	}
+	return radius, allowed
 }
 
 func CheckinHandler(w http.ResponseWriter, r *http.Request) {
@@ -45,60 +56,77 @@ func CheckinHandler(w http.ResponseWriter, r *http.Request) {
 	checkpointID, _ := strconv.Atoi(vars["checkpointId"])
 
 	var input struct {
-		Lat float64 `json:"lat"`
-		Lng float64 `json:"lng"`
+		Lat      float64  `json:"lat"`
+		Lng      float64  `json:"lng"`
+		Accuracy *float64 `json:"accuracy"` // precisión GPS del dispositivo, en metros
 	}
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		http.Error(w, "Formato inválido", http.StatusBadRequest)
 		return
 	}
+	if input.Accuracy != nil && *input.Accuracy < 0 {
+		http.Error(w, "accuracy no puede ser negativo", http.StatusBadRequest)
+		return
+	}
 
-	// Obtener la ruta del evento
-	route, err := repository.GetEventRoute(eventID)
+	// Solo los inscritos pueden hacer checkin
+	registered, err := repository.IsUserRegistered(claims.UserID, eventID)
 	if err != nil {
-		http.Error(w, "No se encontró la ruta", http.StatusNotFound)
+		http.Error(w, "Error verificando inscripción: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !registered {
+		http.Error(w, "No estás inscrito en este evento", http.StatusForbidden)
 		return
 	}
 
-	// Parsear JSON de la ruta con checkpoints
-	var routeData struct {
-		Checkpoints []Checkpoint `json:"checkpoints"`
+	// Obtener la ruta del corredor (la de su categoría o la del evento)
+	route, err := repository.GetRunnerRoute(eventID, claims.UserID)
+	if err != nil {
+		http.Error(w, "No se encontró la ruta", http.StatusNotFound)
+		return
 	}
-	if err := json.Unmarshal(route, &routeData); err != nil {
-		http.Error(w, "Error parseando checkpoints", http.StatusInternalServerError)
+
+	if route == nil {
+		http.Error(w, "El evento no tiene ruta", http.StatusNotFound)
 		return
 	}
 
 	// Buscar el checkpoint
-	var cp *Checkpoint
-	for _, c := range routeData.Checkpoints {
-		if c.ID == checkpointID {
-			// copiar en variable local
-			cp = &Checkpoint{
-				ID:   c.ID,
-				Name: c.Name,
-				Lat:  c.Lat,
-				Lng:  c.Lng,
-				Type: c.Type,
-			}
-			break
-		}
-	}
-	if cp == nil {
+	cp, found := route.Checkpoint(checkpointID)
+	if !found {
 		http.Error(w, "Checkpoint no encontrado", http.StatusNotFound)
 		return
 	}
 
+	settings, err := repository.GetEventCheckinSettings(eventID)
+	if err != nil {
+		http.Error(w, "Evento no encontrado", http.StatusNotFound)
+		return
+	}
+	if err := lifecycle.CheckCheckin(settings.Status); err != nil {
+		http.Error(w, err.Error()+" (estado: "+settings.Status+")", http.StatusConflict)
+		return
+	}
+
 	// Validar distancia
-	dist := haversine(input.Lat, input.Lng, cp.Lat, cp.Lng)
-	if dist > checkpointRadius {
-		http.Error(w, "Fuera de rango del checkpoint", http.StatusBadRequest)
+	dist := geo.Haversine(input.Lat, input.Lng, cp.Lat, cp.Lng)
+	radius, allowed := checkinTolerance(cp, settings.CheckpointRadiusM, input.Accuracy)
+	if dist > allowed {
+		http.Error(w, fmt.Sprintf("Fuera de rango del checkpoint (distancia %.0f m, máximo %.0f m)", dist, allowed), http.StatusBadRequest)
 		return
 	}
 
-	// Guardar checkin
-	if err := repository.CreateCheckin(claims.UserID, eventID, checkpointID, input.Lat, input.Lng); err != nil {
-		http.Error(w, "Error registrando checkin: "+err.Error(), http.StatusInternalServerError)
+	if _, ok := recordCheckin(w, checkinRecord{
+		EventID:     eventID,
+		RunnerID:    claims.UserID,
+		Route:       route,
+		Checkpoint:  cp,
+		CheckinMode: settings.CheckinMode,
+		Lat:         input.Lat,
+		Lng:         input.Lng,
+		Accuracy:    input.Accuracy,
+	}); !ok {
 		return
 	}
 
@@ -106,6 +134,55 @@ func CheckinHandler(w http.ResponseWriter, r *http.Request) {
 		"checkpoint": cp.Name,
 		"status":     "ok",
 		"distance_m": dist,
+		"radius_m":   radius,
+		"allowed_m":  allowed,
 		"message":    "Checkpoint validado correctamente",
 	})
 }
+
+// checkinRecord es un checkin ya validado geográficamente (o registrado por staff)
+type checkinRecord struct {
+	EventID     int
+	RunnerID    int
+	Route       *models.Route
+	Checkpoint  models.Checkpoint
+	CheckinMode string
+	Lat         float64
+	Lng         float64
+	Accuracy    *float64
+	RecordedBy  *int // staff que lo registró; nil = el propio corredor
+}
+
+// recordCheckin valida el orden del recorrido, guarda el checkin y lo propaga a
+// resultados y al stream en vivo. Si falla escribe la respuesta de error y devuelve false.
+func recordCheckin(w http.ResponseWriter, c checkinRecord) (int, bool) {
+	// Guardar checkin, validando orden del recorrido y checkins repetidos en la misma transacción
+	checkSequence := func(visited []int) error {
+		return services.ValidateCheckinSequence(c.Route.Checkpoints, visited, c.Checkpoint.ID, c.CheckinMode)
+	}
+	checkinID, err := repository.CreateCheckin(c.RunnerID, c.EventID, c.Checkpoint.ID, c.Lat, c.Lng, c.Accuracy, c.RecordedBy, checkSequence)
+	if err != nil {
+		if errors.Is(err, services.ErrCheckpointAlreadyVisited) {
+			http.Error(w, "Checkpoint ya registrado para este corredor", http.StatusConflict)
+			return 0, false
+		}
+		if errors.Is(err, services.ErrCheckpointOutOfSequence) {
+			http.Error(w, "Checkpoint fuera de orden: "+err.Error(), http.StatusConflict)
+			return 0, false
+		}
+		// Duplicado por UNIQUE (user_id, event_id, checkpoint_id) en requests concurrentes
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			http.Error(w, "Checkpoint ya registrado para este corredor", http.StatusConflict)
+			return 0, false
+		}
+		http.Error(w, "Error registrando checkin: "+err.Error(), http.StatusInternalServerError)
+		return 0, false
+	}
+
+	// Recalcular resultados con el nuevo checkin; un error aquí no invalida el checkin
+	if err := services.RecomputeEventResults(c.EventID); err != nil {
+		log.Printf("⚠️ Error recalculando resultados del evento %d: %v", c.EventID, err)
+	}
+	publishLiveCheckin(c.EventID, checkinID, c.Checkpoint.Name)
+	return checkinID, true
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"sport-events-backend/internal/middleware"
+	"sport-events-backend/internal/repository"
+	"sport-events-backend/internal/services"
+)
+
+// POST /auth/verify-email
+// Body: {"token": "..."} (el token llega en el enlace del email)
+func VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil || in.Token == "" {
+		http.Error(w, "Debe indicar el token", http.StatusBadRequest)
+		return
+	}
+
+	if err := services.VerifyEmail(in.Token); err != nil {
+		if errors.Is(err, repository.ErrInvalidVerificationToken) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Error verificando el email: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Email verificado"})
+}
+
+// POST /api/me/resend-verification
+func ResendVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := services.ResendEmailVerification(claims.UserID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmailAlreadyVerified):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, services.ErrVerificationThrottled):
+			w.Header().Set("Retry-After", "60")
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		default:
+			http.Error(w, "Error enviando la verificación: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Te enviamos un nuevo enlace de verificación"})
+}
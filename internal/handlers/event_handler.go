@@ -2,15 +2,20 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"time"
 	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/lib/pq"
+	"sport-events-backend/internal/lifecycle"
 	"sport-events-backend/internal/middleware"
 	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/policy"
 	"sport-events-backend/internal/repository"
+	"sport-events-backend/internal/services"
 )
 
 func CreateEvent(w http.ResponseWriter, r *http.Request) {
@@ -20,24 +25,64 @@ func CreateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Solo organizadores pueden crear eventos
-	if claims.Role != "organizer" {
-		http.Error(w, "Forbidden: role 'organizer' required", http.StatusForbidden)
-		return
-	}
-
 	var input struct {
 		Name        string          `json:"name"`
 		Description string          `json:"description"`
 		Type        string          `json:"type"`
 		Date        time.Time       `json:"date"`
 		Location    string          `json:"location"`
-		Route       json.RawMessage `json:"route"`
+		Route       *models.Route   `json:"route"`
+		Capacity    *int            `json:"capacity"` // opcional, nil = sin límite
+		CheckinMode string          `json:"checkin_mode"` // strict (por defecto) | lenient
+		CheckpointRadiusM *float64  `json:"checkpoint_radius_m"` // radio por defecto de los checkpoints
+		BibRangeStart     *int      `json:"bib_range_start"`
+		BibRangeEnd       *int      `json:"bib_range_end"`
+		AgeGroups   models.AgeGroups `json:"age_groups"` // opcional, nil = grupos por defecto
+		RegistrationOpensAt  *time.Time `json:"registration_opens_at"`  // los jobs abren las inscripciones
+		RegistrationClosesAt *time.Time `json:"registration_closes_at"` // y las cierran (por defecto al empezar)
 	}
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	checkinMode, err := normalizeCheckinMode(input.CheckinMode)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateCheckpointRadius(input.CheckpointRadiusM); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateBibRange(input.BibRangeStart, input.BibRangeEnd); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateEventRequired(input.Name, input.Type, input.Location, input.Date); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateEventFuture(input.Date); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateEventCapacity(input.Capacity); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errs := validateRoute(input.Route); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	if errs := input.AgeGroups.Validate(); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	if err := validateRegistrationWindow(input.RegistrationOpensAt, input.RegistrationClosesAt, input.Date); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	metrics := services.ApplyRouteMetrics(input.Route)
 
 	event := models.Event{
 		Name:        input.Name,
@@ -47,6 +92,15 @@ func CreateEvent(w http.ResponseWriter, r *http.Request) {
 		Location:    input.Location,
 		Route:       input.Route,
 		CreatedBy:   claims.UserID,
+		Capacity:    input.Capacity,
+		CheckinMode: checkinMode,
+		CheckpointRadiusM: input.CheckpointRadiusM,
+		RouteMetrics: metrics,
+		BibRangeStart: input.BibRangeStart,
+		BibRangeEnd:   input.BibRangeEnd,
+		AgeGroups:     input.AgeGroups,
+		RegistrationOpensAt:  input.RegistrationOpensAt,
+		RegistrationClosesAt: input.RegistrationClosesAt,
 	}
 
 	id, err := repository.CreateEvent(event)
@@ -54,14 +108,6 @@ func CreateEvent(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Error creando evento: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if err := validateEventRequired(input.Name, input.Type, input.Location, input.Date); err != nil {
-	http.Error(w, err.Error(), http.StatusBadRequest)
-	return
-	}
-	if err := validateEventFuture(input.Date); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
 
 
 	w.WriteHeader(http.StatusCreated)
@@ -83,10 +129,39 @@ func RegisterEventHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = repository.RegisterUserToEvent(claims.UserID, eventID)
+	// Body opcional: {"category_id": 3}; obligatorio si el evento tiene categorías
+	var in struct {
+		CategoryID *int `json:"category_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	result, err := repository.RegisterUserToEvent(claims.UserID, eventID, in.CategoryID)
 	if err != nil {
+		if errors.Is(err, repository.ErrEventNotFound) {
+			http.Error(w, "Evento no encontrado", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, lifecycle.ErrRegistrationClosed) {
+			http.Error(w, "Las inscripciones de este evento no están abiertas", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, repository.ErrCategoryRequired) {
+			http.Error(w, "El evento tiene categorías: indica category_id", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			http.Error(w, "Categoría no encontrada en este evento", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, repository.ErrAlreadyWaitlisted) {
+			http.Error(w, "Ya estabas en la lista de espera de este evento", http.StatusConflict) // 409
+			return
+		}
 		// Duplicado por UNIQUE (user_id, event_id)
-		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+		if pgErr, ok := err.(*pq.Error); (ok && pgErr.Code == "23505") || errors.Is(err, repository.ErrAlreadyRegistered) {
 			http.Error(w, "Ya estabas inscrito en este evento", http.StatusConflict) // 409
 			return
 		}
@@ -94,10 +169,25 @@ func RegisterEventHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	if result.Status == models.RegistrationStatusWaitlisted {
+		// Evento lleno: 202 porque la inscripción aún no es efectiva
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message":     "Evento lleno: quedaste en lista de espera",
+			"status":      result.Status,
+			"position":    result.Position,
+			"category_id": result.CategoryID,
+		})
+		return
+	}
 
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Usuario inscrito correctamente",
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "Usuario inscrito correctamente",
+		"status":      result.Status,
+		"bib_number":  result.BibNumber,
+		"category_id": result.CategoryID,
 	})
 }
 // funcion para cancelar un registro de usuario a un evento
@@ -115,8 +205,17 @@ func CancelRegistrationHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    err = repository.CancelRegistration(claims.UserID, eventID)
+    // Si se libera un cupo, el primero en lista de espera queda inscrito en la misma transacción
+    _, err = repository.CancelRegistration(claims.UserID, eventID)
     if err != nil {
+        if errors.Is(err, repository.ErrEventNotFound) {
+            http.Error(w, "Evento no encontrado", http.StatusNotFound)
+            return
+        }
+        if errors.Is(err, lifecycle.ErrEventLocked) {
+            http.Error(w, "Ya no puedes cancelar la inscripción: el evento empezó o terminó", http.StatusConflict)
+            return
+        }
         http.Error(w, "Error cancelando inscripción: "+err.Error(), http.StatusInternalServerError)
         return
     }
@@ -161,14 +260,8 @@ func GetMyRegistrationsHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(rows)
 }
-// PUT /api/events/{id}  (solo organizer dueño)
+// PUT /api/events/{id}  (permiso event:edit)
 func UpdateEventHandler(w http.ResponseWriter, r *http.Request) {
-	claims, ok := middleware.GetClaims(r)
-	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
 	vars := mux.Vars(r)
 	eventID, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -176,19 +269,68 @@ func UpdateEventHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse input completo (PUT espera todos los campos)
+	if !requireEditable(w, eventID) {
+		return
+	}
+
+	// Parse input completo (PUT reemplaza todos los campos: los omitidos vuelven a su valor por defecto)
 	var in struct {
 		Name        string          `json:"name"`
 		Description string          `json:"description"`
 		Type        string          `json:"type"`
 		Date        time.Time       `json:"date"`
 		Location    string          `json:"location"`
-		Route       json.RawMessage `json:"route"`
+		Route       *models.Route   `json:"route"`
+		Capacity    optionalInt     `json:"capacity"` // obligatorio, null = sin límite
+		CheckinMode string          `json:"checkin_mode"`
+		CheckpointRadiusM *float64  `json:"checkpoint_radius_m"`
+		BibRangeStart     *int      `json:"bib_range_start"`
+		BibRangeEnd       *int      `json:"bib_range_end"`
+		AgeGroups   models.AgeGroups `json:"age_groups"`
+		RegistrationOpensAt  *time.Time `json:"registration_opens_at"`
+		RegistrationClosesAt *time.Time `json:"registration_closes_at"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
 		http.Error(w, "JSON inválido", http.StatusBadRequest)
 		return
 	}
+	checkinMode, err := normalizeCheckinMode(in.CheckinMode)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateCheckpointRadius(in.CheckpointRadiusM); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateBibRange(in.BibRangeStart, in.BibRangeEnd); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !in.Capacity.Set {
+		http.Error(w, "capacity es obligatorio (null = sin límite)", http.StatusBadRequest)
+		return
+	}
+	if err := validateEventCapacity(in.Capacity.Value); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errs := validateRoute(in.Route); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	if errs := in.AgeGroups.Validate(); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	if err := validateRegistrationWindow(in.RegistrationOpensAt, in.RegistrationClosesAt, in.Date); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !requireStableCheckpoints(w, eventID, in.Route) {
+		return
+	}
+	metrics := services.ApplyRouteMetrics(in.Route)
 		if err := validateEventRequired(in.Name, in.Type, in.Location, in.Date); err != nil {
 	http.Error(w, err.Error(), http.StatusBadRequest)
 	return
@@ -214,11 +356,24 @@ func UpdateEventHandler(w http.ResponseWriter, r *http.Request) {
 		Date:        in.Date,
 		Location:    in.Location,
 		Route:       in.Route,
+		Capacity:    in.Capacity.Value,
+		CheckinMode: checkinMode,
+		CheckpointRadiusM: in.CheckpointRadiusM,
+		RouteMetrics: metrics,
+		BibRangeStart: in.BibRangeStart,
+		BibRangeEnd:   in.BibRangeEnd,
+		AgeGroups:     in.AgeGroups,
+		RegistrationOpensAt:  in.RegistrationOpensAt,
+		RegistrationClosesAt: in.RegistrationClosesAt,
+	}
+
+	okUpd, err := repository.UpdateEvent(e)
+	if err != nil {
+		http.Error(w, "Error actualizando evento: "+err.Error(), http.StatusInternalServerError)
+		return
 	}
-
-	okUpd, err := repository.UpdateEventByOwner(e, claims.UserID)
-	if err != nil || !okUpd {
-		http.Error(w, "No autorizado para editar o evento inexistente", http.StatusForbidden)
+	if !okUpd {
+		http.Error(w, "Evento no encontrado", http.StatusNotFound)
 		return
 	}
 
@@ -229,14 +384,8 @@ func UpdateEventHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(updated)
 }
 
-// DELETE /api/events/{id}  (solo organizer dueño)
+// DELETE /api/events/{id}  (permiso event:delete)
 func DeleteEventHandler(w http.ResponseWriter, r *http.Request) {
-	claims, ok := middleware.GetClaims(r)
-	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
 	vars := mux.Vars(r)
 	eventID, err := strconv.Atoi(vars["id"])
 	if err != nil {
@@ -244,25 +393,20 @@ func DeleteEventHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	okDel, err := repository.DeleteEventByOwner(eventID, claims.UserID)
-	if err != nil || !okDel {
-		http.Error(w, "No autorizado para eliminar o evento inexistente", http.StatusForbidden)
+	okDel, err := repository.DeleteEvent(eventID)
+	if errors.Is(err, repository.ErrEventHasRegistrations) {
+		http.Error(w, "No se puede eliminar: el evento tiene inscripciones activas", http.StatusConflict) // 409
 		return
 	}
-	
-	total, err := repository.CountRegistrationsForEvent(eventID)
 	if err != nil {
-		http.Error(w, "Error verificando inscripciones: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Error eliminando evento: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if total > 0 {
-		http.Error(w, "No se puede eliminar: el evento tiene inscripciones activas", http.StatusConflict) // 409
+	if !okDel {
+		http.Error(w, "Evento no encontrado", http.StatusNotFound)
 		return
 	}
 
-
-
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"message": "Evento eliminado"})
 }
@@ -286,13 +430,27 @@ func GetEventDetailHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Evento no encontrado", http.StatusNotFound)
 		return
 	}
+	access, err := repository.GetEventAccess(eventID, claims.UserID)
+	if err != nil {
+		http.Error(w, "Error verificando permisos: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	subject := middleware.Subject(claims)
+	if !lifecycle.IsPublic(evt.Status) && !policy.Decide(subject, access, policy.EventView, nil) {
+		http.Error(w, "Evento no encontrado", http.StatusNotFound)
+		return
+	}
+	if evt.Categories, err = repository.GetEventCategories(eventID); err != nil {
+		http.Error(w, "Error obteniendo categorías: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	resp := map[string]interface{}{
 		"event": evt,
 	}
 
-	// Si es organizer y dueño del evento, incluir inscritos
-	if claims.Role == "organizer" && evt.CreatedBy == claims.UserID {
+	// Con permiso para ver inscritos, se incluyen en la respuesta
+	if policy.Decide(subject, access, policy.RegistrationsRead, nil) {
 		regs, err := repository.GetRegistrationsForEvent(eventID)
 		if err == nil {
 			resp["registrations"] = regs
@@ -304,17 +462,28 @@ func GetEventDetailHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
-// GET /api/events?type=&location=&date= consultas por filtros
+// GET /api/events?type=&location=&date=&status=&min_distance_km=&max_distance_km= consultas por filtros
 func GetEventsHandler(w http.ResponseWriter, r *http.Request) {
-	eventType := r.URL.Query().Get("type")
-	location := r.URL.Query().Get("location")
-	date := r.URL.Query().Get("date")
-	includeCancelled := r.URL.Query().Get("include_cancelled") == "true"
-	var events []models.Event
+	filter := repository.EventFilter{
+		Type:             r.URL.Query().Get("type"),
+		Location:         r.URL.Query().Get("location"),
+		Date:             r.URL.Query().Get("date"),
+		IncludeCancelled: r.URL.Query().Get("include_cancelled") == "true",
+		Status:           r.URL.Query().Get("status"),
+	}
 	var err error
+	if filter.MinDistanceM, err = parseKmParam(r, "min_distance_km"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.MaxDistanceM, err = parseKmParam(r, "max_distance_km"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var events []models.Event
 
-	if eventType != "" || location != "" || date != "" || includeCancelled {
-		events, err = repository.GetEventsFiltered(eventType, location, date, includeCancelled)
+	if filter != (repository.EventFilter{}) {
+		events, err = repository.GetEventsFiltered(filter)
 	} else {
 		events, err = repository.GetAllEvents()
 	}
@@ -328,7 +497,7 @@ func GetEventsHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(events)
 }
-// POST /api/events/{id}/cancel  (solo organizer dueño)
+// POST /api/events/{id}/cancel  (permiso event:status)
 func CancelEventHandler(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.GetClaims(r)
 	if !ok { http.Error(w, "Unauthorized", http.StatusUnauthorized); return }
@@ -341,40 +510,6 @@ func CancelEventHandler(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewDecoder(r.Body).Decode(&in) // reason opcional
 
 	// Si tiene inscritos, permitimos cancelar igual (justamente para avisarles).
-	// Si quisieras bloquear cancelación sin avisar, aquí podrías chequear CountRegistrationsForEvent.
-
-	okUpd, err := repository.CancelEventByOwner(eventID, claims.UserID, in.Reason)
-	if err != nil || !okUpd {
-		http.Error(w, "No autorizado o evento ya cancelado/inexistente", http.StatusForbidden)
-		return
-	}
-
-	evt, _ := repository.GetEventByID(eventID)
-	// TODO (opcional): encolar notificaciones a inscritos (email/push/webhook)
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message": "Evento cancelado",
-		"event":   evt,
-	})
-}
-
-func GetEventRouteHandler(w http.ResponseWriter, r *http.Request) {
-    vars := mux.Vars(r)
-    eventID, err := strconv.Atoi(vars["id"])
-    if err != nil {
-        http.Error(w, "ID de evento inválido", http.StatusBadRequest)
-        return
-    }
-
-    route, err := repository.GetEventRoute(eventID)
-    if err != nil {
-        http.Error(w, "Error obteniendo ruta: "+err.Error(), http.StatusInternalServerError)
-        return
-    }
-
-    w.Header().Set("Content-Type", "application/json")
-    w.Write(route) // devolvemos el JSONB crudo
+	// El aviso a los inscritos se encola en la misma transacción del cambio de estado
+	transitionEvent(w, eventID, claims.UserID, lifecycle.StatusCancelled, in.Reason)
 }
-
-
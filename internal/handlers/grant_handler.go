@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+	"sport-events-backend/internal/middleware"
+	"sport-events-backend/internal/policy"
+	"sport-events-backend/internal/repository"
+)
+
+// GET /api/events/{id}/grants  (permiso grants:manage)
+func GetEventGrantsHandler(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	grants, err := repository.GetEventPermissionGrants(eventID)
+	if err != nil {
+		http.Error(w, "Error obteniendo permisos: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(grants)
+}
+
+// POST /api/events/{id}/grants  (permiso grants:manage)
+// Body: {"user_id": 12} o {"email": "..."}, y "permission" (p. ej. "registrations:read")
+func GrantEventPermissionHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	eventID, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var in struct {
+		UserID     int    `json:"user_id"`
+		Email      string `json:"email"`
+		Permission string `json:"permission"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if !policy.Grantable(policy.Permission(in.Permission)) {
+		http.Error(w, "Permiso no concedible: "+in.Permission, http.StatusBadRequest)
+		return
+	}
+
+	userID := in.UserID
+	if userID == 0 {
+		if in.Email == "" {
+			http.Error(w, "Debe indicar user_id o email", http.StatusBadRequest)
+			return
+		}
+		u, err := repository.GetUserByEmail(in.Email)
+		if err != nil {
+			http.Error(w, "Usuario no encontrado", http.StatusNotFound)
+			return
+		}
+		userID = u.ID
+	} else if _, err := repository.GetUserByID(userID); err != nil {
+		http.Error(w, "Usuario no encontrado", http.StatusNotFound)
+		return
+	}
+
+	id, err := repository.GrantEventPermission(eventID, userID, in.Permission, claims.UserID)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			http.Error(w, "El usuario ya tiene ese permiso", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Error concediendo permiso: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"id": id})
+}
+
+// DELETE /api/events/{id}/grants/{grantId}  (permiso grants:manage)
+func RevokeEventPermissionHandler(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+	grantID, err := strconv.Atoi(mux.Vars(r)["grantId"])
+	if err != nil {
+		http.Error(w, "ID de permiso inválido", http.StatusBadRequest)
+		return
+	}
+
+	removed, err := repository.RevokeEventPermission(eventID, grantID)
+	if err != nil {
+		http.Error(w, "Error quitando permiso: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !removed {
+		http.Error(w, "Permiso no encontrado", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Permiso eliminado"})
+}
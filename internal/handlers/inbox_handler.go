@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sport-events-backend/internal/middleware"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+)
+
+// GET /api/notifications?unread=true&limit=50
+// Bandeja de notificaciones del usuario autenticado
+func GetInboxHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 200 {
+			http.Error(w, "limit debe estar entre 1 y 200", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	items, err := repository.GetUserInbox(claims.UserID, unreadOnly, limit)
+	if err != nil {
+		http.Error(w, "Error obteniendo notificaciones: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	unread, err := repository.CountUnreadInbox(claims.UserID)
+	if err != nil {
+		http.Error(w, "Error obteniendo notificaciones: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"unread":        unread,
+		"notifications": items,
+	})
+}
+
+// POST /api/notifications/{id}/read
+func MarkInboxReadHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de notificación inválido", http.StatusBadRequest)
+		return
+	}
+
+	found, err := repository.MarkInboxRead(claims.UserID, id)
+	if err != nil {
+		http.Error(w, "Error actualizando notificación: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "Notificación no encontrada", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Notificación leída"})
+}
+
+// POST /api/notifications/read-all
+func MarkAllInboxReadHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	n, err := repository.MarkAllInboxRead(claims.UserID)
+	if err != nil {
+		http.Error(w, "Error actualizando notificaciones: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"marked": n})
+}
+
+// GET /api/me/notification-preferences
+func GetNotificationPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	prefs, err := repository.GetNotificationPreferences(claims.UserID)
+	if err != nil {
+		http.Error(w, "Error obteniendo preferencias: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// PUT /api/me/notification-preferences
+// Body: {"email_reminders": false, "email_event_updates": true}; los campos omitidos no cambian
+func UpdateNotificationPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var in struct {
+		EmailReminders    *bool `json:"email_reminders"`
+		EmailEventUpdates *bool `json:"email_event_updates"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := repository.GetNotificationPreferences(claims.UserID)
+	if err != nil {
+		http.Error(w, "Error obteniendo preferencias: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if in.EmailReminders != nil {
+		prefs.EmailReminders = *in.EmailReminders
+	}
+	if in.EmailEventUpdates != nil {
+		prefs.EmailEventUpdates = *in.EmailEventUpdates
+	}
+
+	saved, err := repository.SaveNotificationPreferences(models.NotificationPreferences{
+		UserID:            claims.UserID,
+		EmailReminders:    prefs.EmailReminders,
+		EmailEventUpdates: prefs.EmailEventUpdates,
+	})
+	if err != nil {
+		http.Error(w, "Error guardando preferencias: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"sport-events-backend/internal/auth"
+	"sport-events-backend/internal/live"
+	"sport-events-backend/internal/middleware"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+)
+
+const liveHeartbeatInterval = 15 * time.Second
+
+// POST /api/events/{id}/live/token  (evento público, o permiso event:view si es borrador)
+// Devuelve un token de corta duración para abrir el stream con ?stream_token=,
+// ya que EventSource no puede enviar el header Authorization.
+func LiveTokenHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	eventID, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	token, expires, err := auth.IssueStreamToken(claims.UserID, claims.SessionID, eventID)
+	if err != nil {
+		http.Error(w, "Error generando el token de stream: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"stream_token": token,
+		"expires_at":   expires,
+	})
+}
+
+// GET /api/events/{id}/live  (evento público, o permiso event:view si es borrador) stream SSE de checkins en vivo.
+// Acepta el header Authorization o ?stream_token= (ver LiveTokenHandler).
+// Con Last-Event-ID (header, o ?last_event_id= para clientes que no pueden enviarlo)
+// se reenvían primero los checkins posteriores a ese ID guardados en la BD.
+func LiveEventHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+
+	route, err := repository.GetEventRoute(eventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Evento no encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error obteniendo evento: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	lastID := 0
+	cursor := r.Header.Get("Last-Event-ID")
+	if cursor == "" {
+		cursor = r.URL.Query().Get("last_event_id")
+	}
+	if cursor != "" {
+		if lastID, err = strconv.Atoi(cursor); err != nil || lastID < 0 {
+			http.Error(w, "Last-Event-ID inválido", http.StatusBadRequest)
+			return
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming no soportado", http.StatusInternalServerError)
+		return
+	}
+
+	// Suscribirse antes del replay para no perder checkins entre ambos pasos
+	sub, unsubscribe := live.Default.Subscribe(eventID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // evitar buffering en nginx
+	w.WriteHeader(http.StatusOK)
+
+	if cursor != "" {
+		missed, err := repository.GetLiveCheckinsAfter(eventID, lastID)
+		if err != nil {
+			log.Printf("⚠️ Error en replay del stream del evento %d: %v", eventID, err)
+			return
+		}
+		for _, msg := range missed {
+			msg.CheckpointName = checkpointName(route, msg.CheckpointID)
+			if err := writeLiveCheckin(w, msg); err != nil {
+				return
+			}
+			lastID = msg.ID
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(liveHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sub.Overflow:
+			// Cliente lento: se corta para que reconecte con Last-Event-ID
+			return
+		case msg := <-sub.C:
+			if msg.ID <= lastID {
+				continue // ya enviado en el replay
+			}
+			if err := writeLiveCheckin(w, msg); err != nil {
+				return
+			}
+			lastID = msg.ID
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeLiveCheckin(w http.ResponseWriter, msg models.LiveCheckin) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: checkin\ndata: %s\n\n", msg.ID, data)
+	return err
+}
+
+// publishLiveCheckin envía al stream un checkin ya confirmado en la BD
+func publishLiveCheckin(eventID, checkinID int, checkpointName string) {
+	msg, err := repository.GetLiveCheckin(eventID, checkinID)
+	if err != nil {
+		log.Printf("⚠️ Error publicando checkin %d en vivo: %v", checkinID, err)
+		return
+	}
+	msg.CheckpointName = checkpointName
+	live.Default.Publish(msg)
+}
+
+func checkpointName(route *models.Route, checkpointID int) string {
+	if route == nil {
+		return ""
+	}
+	if cp, ok := route.Checkpoint(checkpointID); ok {
+		return cp.Name
+	}
+	return ""
+}
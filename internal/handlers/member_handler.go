@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sport-events-backend/internal/middleware"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+	"sport-events-backend/internal/services"
+)
+
+// GET /api/events/{id}/members  (permiso event:view)
+func GetEventMembersHandler(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := repository.GetEventMembers(eventID)
+	if err != nil {
+		http.Error(w, "Error obteniendo el equipo: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(members)
+}
+
+// PUT /api/events/{id}/members/{userId}  (permiso members:manage)
+// Body: {"role": "viewer"}
+func UpdateEventMemberHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	eventID, userID, ok := memberParams(w, r)
+	if !ok {
+		return
+	}
+
+	var in struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if !models.InvitableMemberRole(in.Role) {
+		http.Error(w, services.ErrInvalidMemberRole.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := repository.SetEventMemberRole(eventID, userID, in.Role, claims.UserID); err != nil {
+		writeMemberError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"user_id": userID, "role": in.Role})
+}
+
+// DELETE /api/events/{id}/members/{userId}  (permiso members:manage)
+func RemoveEventMemberHandler(w http.ResponseWriter, r *http.Request) {
+	eventID, userID, ok := memberParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := repository.RemoveEventMember(eventID, userID); err != nil {
+		writeMemberError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Miembro eliminado del equipo"})
+}
+
+// POST /api/events/{id}/invitations  (permiso members:manage)
+// Body: {"email": "...", "role": "co_organizer" | "viewer" | "staff"}
+func InviteEventMemberHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	eventID, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var in struct {
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	id, err := services.InviteEventMember(eventID, claims.UserID, in.Email, in.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidInviteEmail), errors.Is(err, services.ErrInvalidMemberRole):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, repository.ErrAlreadyMember):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Error creando la invitación: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"id": id, "message": "Invitación enviada"})
+}
+
+// GET /api/events/{id}/invitations  (permiso members:manage)
+func GetEventInvitationsHandler(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	invitations, err := repository.GetPendingEventInvitations(eventID)
+	if err != nil {
+		http.Error(w, "Error obteniendo invitaciones: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invitations)
+}
+
+// DELETE /api/events/{id}/invitations/{invitationId}  (permiso members:manage)
+func DeleteEventInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+	invitationID, err := strconv.Atoi(mux.Vars(r)["invitationId"])
+	if err != nil {
+		http.Error(w, "ID de invitación inválido", http.StatusBadRequest)
+		return
+	}
+
+	removed, err := repository.DeleteEventInvitation(eventID, invitationID)
+	if err != nil {
+		http.Error(w, "Error anulando la invitación: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !removed {
+		http.Error(w, "Invitación no encontrada", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Invitación anulada"})
+}
+
+// POST /api/invitations/accept  (email verificado)
+// Body: {"token": "..."} — el del enlace recibido por email
+func AcceptEventInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var in struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil || in.Token == "" {
+		http.Error(w, "Debe indicar el token", http.StatusBadRequest)
+		return
+	}
+
+	inv, err := services.AcceptEventInvitation(in.Token, claims.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidInvitation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, repository.ErrInvitationEmailMismatch):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, repository.ErrAlreadyMember):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Error aceptando la invitación: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"event_id":   inv.EventID,
+		"event_name": inv.EventName,
+		"role":       inv.Role,
+		"message":    "Ya formas parte del equipo del evento",
+	})
+}
+
+// memberParams lee {id} y {userId}
+func memberParams(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	eventID, ok := eventIDParam(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	userID, err := strconv.Atoi(mux.Vars(r)["userId"])
+	if err != nil {
+		http.Error(w, "ID de usuario inválido", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return eventID, userID, true
+}
+
+func writeMemberError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrMemberNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrOwnerMember):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "Error actualizando el equipo: "+err.Error(), http.StatusInternalServerError)
+	}
+}
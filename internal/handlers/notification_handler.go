@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sport-events-backend/internal/repository"
+)
+
+// GET /api/events/{id}/notifications  (permiso notifications:read)
+// Resumen por estado de entrega y el detalle de cada notificación
+func GetEventNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := repository.CountEventNotificationsByStatus(eventID)
+	if err != nil {
+		http.Error(w, "Error obteniendo notificaciones: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	notifications, err := repository.GetEventNotifications(eventID)
+	if err != nil {
+		http.Error(w, "Error obteniendo notificaciones: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"event_id":      eventID,
+		"summary":       summary,
+		"notifications": notifications,
+	})
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"sport-events-backend/internal/middleware"
+	"sport-events-backend/internal/repository"
+)
+
+// POST /api/organizer-applications  (runner con email verificado)
+// Body: {"organization": "Club Andino", "message": "Organizamos trail desde 2019"}
+func CreateOrganizerApplicationHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var in struct {
+		Organization string `json:"organization"`
+		Message      string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if len(in.Organization) > 150 || len(in.Message) > 2000 {
+		http.Error(w, "organization admite 150 caracteres y message 2000", http.StatusBadRequest)
+		return
+	}
+
+	app, err := repository.CreateOrganizerApplication(claims.UserID, strings.TrimSpace(in.Organization), strings.TrimSpace(in.Message))
+	if err != nil {
+		if errors.Is(err, repository.ErrApplicationPending) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Error creando la solicitud: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(app)
+}
+
+// GET /api/me/organizer-application
+// Estado de la última solicitud del usuario
+func GetMyOrganizerApplicationHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	app, err := repository.GetLatestOrganizerApplication(claims.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrApplicationNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error obteniendo la solicitud: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(app)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+
+	"sport-events-backend/internal/repository"
+	"sport-events-backend/internal/services"
+)
+
+// POST /auth/forgot-password
+// Body: {"email": "..."}. Responde siempre 202 para no revelar qué emails tienen cuenta.
+func ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil || in.Email == "" {
+		http.Error(w, "Debe indicar el email", http.StatusBadRequest)
+		return
+	}
+
+	if err := services.RequestPasswordReset(in.Email, clientIP(r)); err != nil {
+		log.Printf("⚠️ Error solicitando restablecimiento de contraseña: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Si el email tiene una cuenta, recibirás un enlace para restablecer la contraseña",
+	})
+}
+
+// POST /auth/reset-password
+// Body: {"token": "...", "password": "..."}
+func ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if in.Token == "" {
+		http.Error(w, "Debe indicar el token", http.StatusBadRequest)
+		return
+	}
+
+	if err := services.ResetPassword(in.Token, in.Password); err != nil {
+		switch {
+		case errors.Is(err, services.ErrWeakPassword):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, repository.ErrInvalidResetToken):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Error restableciendo la contraseña: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Contraseña actualizada; inicia sesión de nuevo"})
+}
+
+// clientIP devuelve la IP del cliente sin el puerto
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+)
+
+// maxReminderOffset: los recordatorios se pueden programar hasta 60 días antes
+const maxReminderOffset = 60 * 24 * 60
+
+// GET /api/events/{id}/reminders  (permiso event:view)
+func GetEventRemindersHandler(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	reminders, err := repository.GetEventReminders(eventID)
+	if err != nil {
+		http.Error(w, "Error obteniendo recordatorios: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reminders)
+}
+
+// POST /api/events/{id}/reminders  (permiso event:edit)
+// Body: {"offset_minutes": 1440, "message": "Retiro de kits el sábado de 9 a 18 h"}
+func CreateEventReminderHandler(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+	if !requireEditable(w, eventID) {
+		return
+	}
+
+	var in struct {
+		OffsetMinutes int     `json:"offset_minutes"`
+		Message       *string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if in.OffsetMinutes <= 0 || in.OffsetMinutes > maxReminderOffset {
+		http.Error(w, "offset_minutes debe estar entre 1 y "+strconv.Itoa(maxReminderOffset), http.StatusBadRequest)
+		return
+	}
+	if in.Message != nil {
+		msg := strings.TrimSpace(*in.Message)
+		if len(msg) > 1000 {
+			http.Error(w, "message no puede superar 1000 caracteres", http.StatusBadRequest)
+			return
+		}
+		in.Message = &msg
+		if msg == "" {
+			in.Message = nil
+		}
+	}
+
+	reminder, err := repository.CreateEventReminder(models.EventReminder{
+		EventID:       eventID,
+		OffsetMinutes: in.OffsetMinutes,
+		Message:       in.Message,
+	})
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			http.Error(w, "Ya existe un recordatorio con esa anticipación", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Error creando recordatorio: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reminder)
+}
+
+// DELETE /api/events/{id}/reminders/{reminderId}  (permiso event:edit)
+func DeleteEventReminderHandler(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := eventIDParam(w, r)
+	if !ok {
+		return
+	}
+	reminderID, err := strconv.Atoi(mux.Vars(r)["reminderId"])
+	if err != nil {
+		http.Error(w, "ID de recordatorio inválido", http.StatusBadRequest)
+		return
+	}
+
+	if err := repository.DeleteEventReminder(eventID, reminderID); err != nil {
+		if errors.Is(err, repository.ErrReminderNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error eliminando recordatorio: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Recordatorio eliminado"})
+}
+
+// eventIDParam lee {id}; el permiso sobre el evento ya lo validó el middleware
+func eventIDParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	eventID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return 0, false
+	}
+	return eventID, true
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+	"sport-events-backend/internal/services"
+)
+
+// GET /api/events/{id}/results?category_id=  clasificación general o de una categoría
+func GetEventResultsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := repository.GetEventStatus(eventID); err != nil {
+		http.Error(w, "Evento no encontrado", http.StatusNotFound)
+		return
+	}
+
+	var categoryID *int
+	if raw := r.URL.Query().Get("category_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			http.Error(w, "category_id inválido", http.StatusBadRequest)
+			return
+		}
+		categoryID = &id
+	}
+
+	results, err := repository.GetEventResults(eventID, categoryID)
+	if err != nil {
+		http.Error(w, "Error obteniendo resultados: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"event_id": eventID,
+		"results":  results,
+	})
+}
+
+// GET /api/events/{id}/results/age-groups?category_id=&top=3
+// Podios por categoría, género y grupo de edad (edad cumplida el día de la carrera)
+func GetAgeGroupResultsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+
+	evt, err := repository.GetEventByID(eventID)
+	if err != nil {
+		http.Error(w, "Evento no encontrado", http.StatusNotFound)
+		return
+	}
+
+	var categoryID *int
+	if raw := r.URL.Query().Get("category_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			http.Error(w, "category_id inválido", http.StatusBadRequest)
+			return
+		}
+		categoryID = &id
+	}
+	top := 0
+	if raw := r.URL.Query().Get("top"); raw != "" {
+		if top, err = strconv.Atoi(raw); err != nil || top < 0 {
+			http.Error(w, "top debe ser un entero positivo", http.StatusBadRequest)
+			return
+		}
+	}
+
+	results, err := repository.GetFinishedResultsWithProfile(eventID, categoryID)
+	if err != nil {
+		http.Error(w, "Error obteniendo resultados: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	groups := evt.AgeGroups
+	if len(groups) == 0 {
+		groups = models.DefaultAgeGroups
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"event_id":   eventID,
+		"race_day":   evt.Date,
+		"age_groups": groups.Normalize(),
+		"rankings":   services.AgeGroupRankings(evt.Date, groups, results, top),
+	})
+}
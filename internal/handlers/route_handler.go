@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+	"sport-events-backend/internal/routeformat"
+	"sport-events-backend/internal/services"
+)
+
+const maxRouteUploadBytes = 10 << 20 // 10 MB
+
+// GET /api/events/{id}/route?format=json|gpx|kml|geojson
+func GetEventRouteHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+
+	evt, err := repository.GetEventByID(eventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Evento no encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error obteniendo ruta: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" || format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(evt.Route)
+		return
+	}
+
+	if evt.Route == nil {
+		http.Error(w, "El evento no tiene ruta", http.StatusNotFound)
+		return
+	}
+
+	var (
+		buf         bytes.Buffer
+		contentType string
+	)
+	switch format {
+	case routeformat.FormatGPX:
+		contentType = "application/gpx+xml"
+		err = routeformat.EncodeGPX(&buf, evt.Name, *evt.Route)
+	case routeformat.FormatKML:
+		contentType = "application/vnd.google-earth.kml+xml"
+		err = routeformat.EncodeKML(&buf, evt.Name, *evt.Route)
+	case routeformat.FormatGeoJSON:
+		contentType = "application/geo+json"
+		err = routeformat.EncodeGeoJSON(&buf, evt.Name, *evt.Route)
+	default:
+		http.Error(w, "format debe ser json, gpx, kml o geojson", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error generando archivo de ruta: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d.%s"`, eventID, format))
+	w.Write(buf.Bytes())
+}
+
+// POST /api/events/{id}/route  (permiso event:edit)
+// Acepta un archivo GPX 1.1 o KML, en el body o como campo "file" de multipart/form-data.
+func UploadEventRouteHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+	if !requireEditable(w, eventID) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRouteUploadBytes)
+	data, err := readRouteUpload(r)
+	if err != nil {
+		http.Error(w, "No se pudo leer el archivo: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format, err := routeformat.Detect(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+	route, err := routeformat.Parse(format, bytes.NewReader(data))
+	if err != nil {
+		http.Error(w, "Archivo de ruta inválido: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errs := route.Validate(); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	if !requireStableCheckpoints(w, eventID, route) {
+		return
+	}
+
+	metrics := services.ApplyRouteMetrics(route)
+	okUpd, err := repository.UpdateEventRoute(eventID, *route, metrics)
+	if err != nil {
+		http.Error(w, "Error guardando la ruta: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !okUpd {
+		http.Error(w, "Evento no encontrado", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Ruta importada desde " + strings.ToUpper(format),
+		"route":   route,
+		"metrics": metrics,
+	})
+}
+
+func readRouteUpload(r *http.Request) ([]byte, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return io.ReadAll(file)
+	}
+	return io.ReadAll(r.Body)
+}
+
+// requireStableCheckpoints responde 409 si el evento ya tiene checkins y la nueva ruta
+// cambia los IDs de sus checkpoints: los checkins y el staff asignado apuntan a esos IDs.
+func requireStableCheckpoints(w http.ResponseWriter, eventID int, next *models.Route) bool {
+	hasCheckins, err := repository.EventHasCheckins(eventID)
+	if err != nil {
+		http.Error(w, "Error verificando checkins: "+err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if !hasCheckins {
+		return true
+	}
+	current, err := repository.GetEventRoute(eventID)
+	if err != nil {
+		http.Error(w, "Error obteniendo la ruta: "+err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if !sameCheckpointIDs(current, next) {
+		http.Error(w, "El evento ya tiene checkins: la nueva ruta debe conservar los IDs de los checkpoints", http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+// requireStableCategoryCheckpoints es requireStableCheckpoints para la ruta de una categoría.
+// Compara las rutas efectivas (una categoría sin ruta propia usa la del evento).
+func requireStableCategoryCheckpoints(w http.ResponseWriter, eventID, categoryID int, next *models.Route) bool {
+	hasCheckins, err := repository.EventHasCheckins(eventID)
+	if err != nil {
+		http.Error(w, "Error verificando checkins: "+err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if !hasCheckins {
+		return true
+	}
+	cat, err := repository.GetCategory(eventID, categoryID)
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		http.Error(w, "Categoría no encontrada", http.StatusNotFound)
+		return false
+	}
+	if err != nil {
+		http.Error(w, "Error obteniendo la categoría: "+err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	eventRoute, err := repository.GetEventRoute(eventID)
+	if err != nil {
+		http.Error(w, "Error obteniendo la ruta: "+err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	current := cat.Route
+	if current == nil {
+		current = eventRoute
+	}
+	if next == nil {
+		next = eventRoute
+	}
+	if !sameCheckpointIDs(current, next) {
+		http.Error(w, "El evento ya tiene checkins: la nueva ruta de la categoría debe conservar los IDs de los checkpoints", http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+func sameCheckpointIDs(a, b *models.Route) bool {
+	ids := map[int]bool{}
+	if a != nil {
+		for _, cp := range a.Checkpoints {
+			ids[cp.ID] = true
+		}
+	}
+	n := 0
+	if b != nil {
+		for _, cp := range b.Checkpoints {
+			if !ids[cp.ID] {
+				return false
+			}
+			n++
+		}
+	}
+	return n == len(ids)
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sport-events-backend/internal/auth"
+	"sport-events-backend/internal/middleware"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+)
+
+// POST /auth/refresh
+// Body: {"refresh_token": "..."}. Devuelve un access token nuevo y otro refresh token;
+// el anterior deja de valer (si se vuelve a usar, se revoca la sesión).
+func RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil || in.RefreshToken == "" {
+		http.Error(w, "Debe indicar el refresh_token", http.StatusBadRequest)
+		return
+	}
+
+	pair, err := auth.Refresh(in.RefreshToken, clientIP(r))
+	if err != nil {
+		if auth.IsInvalidRefresh(err) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, "Error renovando la sesión: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pair)
+}
+
+// POST /auth/logout
+// Body: {"refresh_token": "..."}. Revoca la sesión; funciona aunque el access token ya venció.
+func LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil || in.RefreshToken == "" {
+		http.Error(w, "Debe indicar el refresh_token", http.StatusBadRequest)
+		return
+	}
+
+	if err := auth.Logout(in.RefreshToken); err != nil {
+		http.Error(w, "Error cerrando la sesión: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Sesión cerrada"})
+}
+
+// POST /api/auth/logout-all
+// Cierra la sesión en todos los dispositivos (incluido el actual)
+func LogoutAllHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	n, err := repository.RevokeUserSessions(claims.UserID, models.SessionRevokedLogoutAll)
+	if err != nil {
+		http.Error(w, "Error cerrando las sesiones: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"revoked": n})
+}
+
+// GET /api/me/sessions
+func GetSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sessions, err := repository.GetActiveSessions(claims.UserID)
+	if err != nil {
+		http.Error(w, "Error obteniendo sesiones: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == claims.SessionID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sessions)
+}
+
+// DELETE /api/me/sessions/{id}
+// Cierra una sesión concreta (p.ej. la de un teléfono perdido)
+func RevokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	sessionID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de sesión inválido", http.StatusBadRequest)
+		return
+	}
+
+	revoked, err := repository.RevokeSession(claims.UserID, sessionID)
+	if err != nil {
+		http.Error(w, "Error cerrando la sesión: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !revoked {
+		http.Error(w, repository.ErrSessionNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Sesión cerrada"})
+}
+
+// GET /.well-known/jwks.json  (público)
+func JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(map[string]interface{}{"keys": auth.PublicJWKS()})
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+	"sport-events-backend/internal/lifecycle"
+	"sport-events-backend/internal/middleware"
+	"sport-events-backend/internal/policy"
+	"sport-events-backend/internal/repository"
+)
+
+// POST /api/events/{id}/staff  (permiso staff:manage)
+// Body: {"user_id": 12} o {"email": "..."}, y opcionalmente "checkpoint_id"
+func AssignStaffHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+
+	var in struct {
+		UserID       int    `json:"user_id"`
+		Email        string `json:"email"`
+		CheckpointID *int   `json:"checkpoint_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	userID := in.UserID
+	if userID == 0 {
+		if in.Email == "" {
+			http.Error(w, "Debe indicar user_id o email", http.StatusBadRequest)
+			return
+		}
+		u, err := repository.GetUserByEmail(in.Email)
+		if err != nil {
+			http.Error(w, "Usuario no encontrado", http.StatusNotFound)
+			return
+		}
+		userID = u.ID
+	} else if _, err := repository.GetUserByID(userID); err != nil {
+		http.Error(w, "Usuario no encontrado", http.StatusNotFound)
+		return
+	}
+
+	if in.CheckpointID != nil {
+		found, err := eventHasCheckpoint(eventID, *in.CheckpointID)
+		if err != nil {
+			http.Error(w, "Error obteniendo la ruta: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			http.Error(w, "Checkpoint no encontrado", http.StatusBadRequest)
+			return
+		}
+	}
+
+	id, err := repository.AssignStaff(eventID, userID, in.CheckpointID, claims.UserID)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			http.Error(w, "El usuario ya tiene esa asignación", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Error asignando staff: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"id": id})
+}
+
+// GET /api/events/{id}/staff  (permiso staff:manage)
+func GetEventStaffHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+
+	staff, err := repository.GetEventStaff(eventID)
+	if err != nil {
+		http.Error(w, "Error obteniendo staff: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(staff)
+}
+
+// DELETE /api/events/{id}/staff/{staffId}  (permiso staff:manage)
+func RemoveStaffHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+	staffID, err := strconv.Atoi(vars["staffId"])
+	if err != nil {
+		http.Error(w, "ID de staff inválido", http.StatusBadRequest)
+		return
+	}
+
+	removed, err := repository.RemoveStaff(eventID, staffID)
+	if err != nil {
+		http.Error(w, "Error eliminando staff: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !removed {
+		http.Error(w, "Asignación no encontrada", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Staff eliminado"})
+}
+
+// POST /api/events/{id}/checkpoint/{checkpointId}/staff-checkin
+// Un staff asignado (o quien tenga checkin:record sobre el evento) registra el paso de un corredor sin validar GPS.
+func StaffCheckinHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+	checkpointID, err := strconv.Atoi(vars["checkpointId"])
+	if err != nil {
+		http.Error(w, "ID de checkpoint inválido", http.StatusBadRequest)
+		return
+	}
+
+	allowed, err := policy.CanAtCheckpoint(middleware.Subject(claims), eventID, checkpointID, policy.CheckinRecord)
+	if err != nil {
+		http.Error(w, "Error verificando permisos: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		http.Error(w, "No eres staff de este checkpoint", http.StatusForbidden)
+		return
+	}
+
+	// Se identifica al corredor por dorsal o por ID
+	var in struct {
+		RunnerID int `json:"runner_id"`
+		Bib      int `json:"bib"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if in.RunnerID == 0 && in.Bib == 0 {
+		http.Error(w, "Debe indicar bib o runner_id", http.StatusBadRequest)
+		return
+	}
+	if in.RunnerID == 0 {
+		reg, err := repository.GetRegistrationByBib(eventID, in.Bib)
+		if err != nil {
+			http.Error(w, "Dorsal no encontrado en este evento", http.StatusNotFound)
+			return
+		}
+		in.RunnerID = reg.UserID
+	}
+
+	registered, err := repository.IsUserRegistered(in.RunnerID, eventID)
+	if err != nil {
+		http.Error(w, "Error verificando inscripción: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !registered {
+		http.Error(w, "El corredor no está inscrito en este evento", http.StatusNotFound)
+		return
+	}
+
+	route, err := repository.GetRunnerRoute(eventID, in.RunnerID)
+	if err != nil || route == nil {
+		http.Error(w, "El evento no tiene ruta", http.StatusNotFound)
+		return
+	}
+	cp, found := route.Checkpoint(checkpointID)
+	if !found {
+		http.Error(w, "Checkpoint no encontrado", http.StatusNotFound)
+		return
+	}
+	settings, err := repository.GetEventCheckinSettings(eventID)
+	if err != nil {
+		http.Error(w, "Evento no encontrado", http.StatusNotFound)
+		return
+	}
+	if err := lifecycle.CheckCheckin(settings.Status); err != nil {
+		http.Error(w, err.Error()+" (estado: "+settings.Status+")", http.StatusConflict)
+		return
+	}
+
+	// Sin geofence: se guardan las coordenadas del checkpoint
+	recordedBy := claims.UserID
+	checkinID, ok := recordCheckin(w, checkinRecord{
+		EventID:     eventID,
+		RunnerID:    in.RunnerID,
+		Route:       route,
+		Checkpoint:  cp,
+		CheckinMode: settings.CheckinMode,
+		Lat:         cp.Lat,
+		Lng:         cp.Lng,
+		RecordedBy:  &recordedBy,
+	})
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":          checkinID,
+		"checkpoint":  cp.Name,
+		"runner_id":   in.RunnerID,
+		"bib":         in.Bib,
+		"recorded_by": recordedBy,
+		"status":      "ok",
+		"message":     "Checkin registrado por staff",
+	})
+}
+
+// eventHasCheckpoint busca el checkpoint en la ruta del evento o en la de alguna categoría
+func eventHasCheckpoint(eventID, checkpointID int) (bool, error) {
+	route, err := repository.GetEventRoute(eventID)
+	if err != nil {
+		return false, err
+	}
+	if route != nil {
+		if _, found := route.Checkpoint(checkpointID); found {
+			return true, nil
+		}
+	}
+	categories, err := repository.GetEventCategories(eventID)
+	if err != nil {
+		return false, err
+	}
+	for _, cat := range categories {
+		if cat.Route == nil {
+			continue
+		}
+		if _, found := cat.Route.Checkpoint(checkpointID); found {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sport-events-backend/internal/lifecycle"
+	"sport-events-backend/internal/middleware"
+	"sport-events-backend/internal/repository"
+)
+
+// POST /api/events/{id}/status  (permiso event:status)
+// Body: {"status": "registration_open", "reason": "opcional"}
+func TransitionEventStatusHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+
+	var in struct {
+		Status string `json:"status"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if !lifecycle.Valid(in.Status) {
+		http.Error(w, "status desconocido: "+in.Status, http.StatusBadRequest)
+		return
+	}
+
+	transitionEvent(w, eventID, claims.UserID, in.Status, in.Reason)
+}
+
+// transitionEvent aplica el cambio de estado y responde con el evento actualizado.
+// Una transición no permitida responde 409 con los estados posibles.
+func transitionEvent(w http.ResponseWriter, eventID, userID int, to, reason string) {
+	changedBy := userID
+	t, err := repository.TransitionEventStatus(eventID, to, &changedBy, reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrEventNotFound):
+			http.Error(w, "Evento no encontrado", http.StatusNotFound)
+		case errors.Is(err, lifecycle.ErrInvalidTransition):
+			current, _ := repository.GetEventStatus(eventID)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":   err.Error(),
+				"status":  current,
+				"allowed": lifecycle.Next(current),
+			})
+		default:
+			http.Error(w, "Error cambiando estado: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	evt, _ := repository.GetEventByID(eventID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"event":      evt,
+		"transition": t,
+		"allowed":    lifecycle.Next(t.ToStatus),
+	})
+}
+
+// GET /api/events/{id}/status-history  (permiso event:view)
+func GetEventStatusHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+		return
+	}
+
+	history, err := repository.GetEventStatusTransitions(eventID)
+	if err != nil {
+		http.Error(w, "Error obteniendo historial: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// requireEditable responde 409 si el estado del evento no permite modificarlo
+func requireEditable(w http.ResponseWriter, eventID int) bool {
+	status, err := repository.GetEventStatus(eventID)
+	if err != nil {
+		http.Error(w, "Evento no encontrado", http.StatusNotFound)
+		return false
+	}
+	if err := lifecycle.CheckEdit(status); err != nil {
+		http.Error(w, err.Error()+" (estado: "+status+")", http.StatusConflict)
+		return false
+	}
+	return true
+}
@@ -3,9 +3,13 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
+	"time"
 
+	"sport-events-backend/internal/mailer"
 	"sport-events-backend/internal/middleware"
-	
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/policy"
 	"sport-events-backend/internal/repository"
 )
 
@@ -28,8 +32,8 @@ func GetMeHandler(w http.ResponseWriter, r *http.Request) {
 		"profile": user,
 	}
 
-	switch user.Role {
-	case "organizer":
+	switch {
+	case policy.Allows(user.Role, policy.EventCreate):
 		events, err := repository.GetEventsByCreator(user.ID)
 		if err == nil {
 			resp["events_created"] = events
@@ -45,6 +49,91 @@ func GetMeHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Eventos de otros organizadores en cuyo equipo participa
+	if teams, err := repository.GetMemberEvents(user.ID); err == nil {
+		resp["event_teams"] = teams
+	} else {
+		resp["event_teams"] = []interface{}{}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
-}
\ No newline at end of file
+}
+
+// PUT /api/me  actualiza el perfil propio: {"name", "birthdate": "YYYY-MM-DD", "gender", "locale"}
+// Los campos que no vienen se mantienen; birthdate/gender "" los borra.
+func UpdateMeHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var in struct {
+		Name      *string `json:"name"`
+		Birthdate *string `json:"birthdate"`
+		Gender    *string `json:"gender"`
+		Locale    *string `json:"locale"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	user, err := repository.GetUserByID(claims.UserID)
+	if err != nil {
+		http.Error(w, "Usuario no encontrado", http.StatusNotFound)
+		return
+	}
+
+	if in.Name != nil {
+		name := strings.TrimSpace(*in.Name)
+		if name == "" {
+			http.Error(w, "name no puede estar vacío", http.StatusBadRequest)
+			return
+		}
+		user.Name = name
+	}
+	if in.Birthdate != nil {
+		if *in.Birthdate == "" {
+			user.Birthdate = nil
+		} else {
+			d, err := time.Parse("2006-01-02", *in.Birthdate)
+			if err != nil {
+				http.Error(w, "birthdate debe tener formato YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			if d.After(time.Now()) {
+				http.Error(w, "birthdate no puede ser futura", http.StatusBadRequest)
+				return
+			}
+			user.Birthdate = &d
+		}
+	}
+	if in.Gender != nil {
+		if *in.Gender == "" {
+			user.Gender = nil
+		} else if !models.ValidGender(*in.Gender) {
+			http.Error(w, "gender debe ser 'female', 'male' u 'other'", http.StatusBadRequest)
+			return
+		} else {
+			user.Gender = in.Gender
+		}
+	}
+
+	if in.Locale != nil {
+		if !mailer.SupportedLocale(*in.Locale) {
+			http.Error(w, "locale debe ser 'es' o 'en'", http.StatusBadRequest)
+			return
+		}
+		user.Locale = *in.Locale
+	}
+
+	if err := repository.UpdateUserProfile(user); err != nil {
+		http.Error(w, "Error actualizando perfil: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
+	"net/http"
+	"strconv"
 	"time"
+
+	"sport-events-backend/internal/models"
 )
 
 func validateEventRequired(name, typ, location string, date time.Time) error {
@@ -22,3 +27,99 @@ func validateEventFuture(date time.Time) error {
 	}
 	return nil
 }
+
+// validateRoute valida la ruta si viene en el request (es opcional)
+func validateRoute(route *models.Route) models.ValidationErrors {
+	if route == nil {
+		return nil
+	}
+	return route.Validate()
+}
+
+// writeValidationErrors responde 422 con el detalle por campo
+func writeValidationErrors(w http.ResponseWriter, errs models.ValidationErrors) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":  "Datos inválidos",
+		"errors": errs,
+	})
+}
+
+// optionalInt distingue un campo ausente del JSON (Set = false) de uno enviado como null
+// (p. ej. capacity en el PUT del evento: obligatorio, pero null significa sin límite)
+type optionalInt struct {
+	Set   bool
+	Value *int
+}
+
+func (o *optionalInt) UnmarshalJSON(b []byte) error {
+	o.Set = true
+	return json.Unmarshal(b, &o.Value)
+}
+
+func validateEventCapacity(capacity *int) error {
+	if capacity != nil && *capacity < 0 {
+		return errors.New("capacity no puede ser negativo")
+	}
+	return nil
+}
+
+func validateCheckpointRadius(radius *float64) error {
+	if radius != nil && *radius <= 0 {
+		return errors.New("checkpoint_radius_m debe ser mayor que 0")
+	}
+	return nil
+}
+
+func validateBibRange(start, end *int) error {
+	if start != nil && *start < 1 {
+		return errors.New("bib_range_start debe ser mayor que 0")
+	}
+	if end != nil {
+		first := 1
+		if start != nil {
+			first = *start
+		}
+		if *end < first {
+			return errors.New("bib_range_end debe ser mayor o igual que bib_range_start")
+		}
+	}
+	return nil
+}
+
+// validateRegistrationWindow: la apertura antes del cierre y el cierre no después del evento
+func validateRegistrationWindow(opens, closes *time.Time, date time.Time) error {
+	if opens != nil && closes != nil && !opens.Before(*closes) {
+		return errors.New("registration_opens_at debe ser anterior a registration_closes_at")
+	}
+	if closes != nil && closes.After(date) {
+		return errors.New("registration_closes_at no puede ser posterior a la fecha del evento")
+	}
+	return nil
+}
+
+// parseKmParam lee un query param en kilómetros y lo devuelve en metros (nil si no viene)
+func parseKmParam(r *http.Request, name string) (*float64, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	km, err := strconv.ParseFloat(raw, 64)
+	if err != nil || km < 0 {
+		return nil, errors.New(name + " debe ser un número positivo")
+	}
+	m := km * 1000
+	return &m, nil
+}
+
+// normalizeCheckinMode aplica el valor por defecto (strict) y valida el modo
+func normalizeCheckinMode(mode string) (string, error) {
+	switch mode {
+	case "":
+		return models.CheckinModeStrict, nil
+	case models.CheckinModeStrict, models.CheckinModeLenient:
+		return mode, nil
+	}
+	return "", errors.New("checkin_mode debe ser 'strict' o 'lenient'")
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sport-events-backend/internal/middleware"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/policy"
+	"sport-events-backend/internal/repository"
+	"sport-events-backend/internal/webhooks"
+)
+
+// POST /api/webhooks  (organizer)
+// Body: {"url": "https://...", "event_id": 3, "event_types": ["registration.created"]}
+// Con event_id hace falta el permiso event:edit sobre el evento. Sin event_id aplica a todos
+// los eventos que el usuario puede editar; sin event_types, a todos los tipos.
+// El secreto de firma solo se devuelve en esta respuesta.
+func CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var in struct {
+		URL        string   `json:"url"`
+		EventID    *int     `json:"event_id"`
+		EventTypes []string `json:"event_types"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	var errs models.ValidationErrors
+	if err := webhooks.ValidateURL(r.Context(), in.URL); err != nil {
+		errs = append(errs, models.FieldError{Field: "url", Message: err.Error()})
+	}
+	for i, t := range in.EventTypes {
+		if !models.ValidWebhookEventType(t) {
+			errs = append(errs, models.FieldError{Field: "event_types[" + strconv.Itoa(i) + "]", Message: "tipo desconocido: " + t})
+		}
+	}
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	if in.EventID != nil {
+		allowed, err := policy.CanOnEvent(middleware.Subject(claims), *in.EventID, policy.EventEdit)
+		if err != nil {
+			http.Error(w, "Error verificando permisos: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			http.Error(w, "No tienes permiso sobre este evento", http.StatusForbidden)
+			return
+		}
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		http.Error(w, "Error generando el secreto", http.StatusInternalServerError)
+		return
+	}
+	if in.EventTypes == nil {
+		in.EventTypes = []string{}
+	}
+	ep, err := repository.CreateWebhookEndpoint(models.WebhookEndpoint{
+		OwnerID:    claims.UserID,
+		EventID:    in.EventID,
+		URL:        in.URL,
+		Secret:     secret,
+		EventTypes: in.EventTypes,
+	})
+	if err != nil {
+		http.Error(w, "Error creando webhook: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"webhook": ep,
+		"secret":  ep.Secret,
+	})
+}
+
+// GET /api/webhooks  (organizer)
+func GetWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	endpoints, err := repository.GetWebhookEndpointsByOwner(claims.UserID)
+	if err != nil {
+		http.Error(w, "Error obteniendo webhooks: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"webhooks":    endpoints,
+		"event_types": models.WebhookEventTypes,
+	})
+}
+
+// DELETE /api/webhooks/{id}  (organizer dueño)
+func DeleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de webhook inválido", http.StatusBadRequest)
+		return
+	}
+
+	removed, err := repository.DeleteWebhookEndpoint(id, claims.UserID)
+	if err != nil {
+		http.Error(w, "Error eliminando webhook: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !removed {
+		http.Error(w, repository.ErrWebhookNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Webhook eliminado"})
+}
+
+// GET /api/webhooks/{id}/deliveries?limit=50  (organizer dueño)
+func GetWebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	ep, ok := ownWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 500 {
+			http.Error(w, "limit debe estar entre 1 y 500", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := repository.GetWebhookDeliveries(ep.ID, limit)
+	if err != nil {
+		http.Error(w, "Error obteniendo entregas: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// POST /api/webhooks/{id}/deliveries/{deliveryId}/replay  (organizer dueño)
+// Encola de nuevo el mismo payload como una entrega nueva
+func ReplayWebhookDeliveryHandler(w http.ResponseWriter, r *http.Request) {
+	ep, ok := ownWebhook(w, r)
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.Atoi(mux.Vars(r)["deliveryId"])
+	if err != nil {
+		http.Error(w, "ID de entrega inválido", http.StatusBadRequest)
+		return
+	}
+
+	d, err := repository.ReplayWebhookDelivery(ep.ID, deliveryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookDeliveryNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error reenviando la entrega: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(d)
+}
+
+// ownWebhook carga el webhook {id} del organizer autenticado (404 si es de otro)
+func ownWebhook(w http.ResponseWriter, r *http.Request) (models.WebhookEndpoint, bool) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return models.WebhookEndpoint{}, false
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de webhook inválido", http.StatusBadRequest)
+		return models.WebhookEndpoint{}, false
+	}
+	ep, err := repository.GetWebhookEndpoint(id, claims.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return ep, false
+		}
+		http.Error(w, "Error obteniendo webhook: "+err.Error(), http.StatusInternalServerError)
+		return ep, false
+	}
+	return ep, true
+}
@@ -0,0 +1,58 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"sport-events-backend/internal/repository"
+)
+
+// tokenRetention: cuánto se conservan los tokens usados o vencidos (para auditoría)
+const tokenRetention = 7 * 24 * time.Hour
+
+// AuthJobs devuelve los jobs de limpieza de tokens (incluidas las invitaciones) y sesiones
+func AuthJobs(interval time.Duration) []Job {
+	return []Job{
+		{Name: "purge_password_resets", Interval: interval, Run: func(ctx context.Context) error {
+			n, err := repository.PurgePasswordResetTokens(tokenRetention)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				log.Printf("🧹 %d tokens de restablecimiento eliminados", n)
+			}
+			return nil
+		}},
+		{Name: "purge_sessions", Interval: interval, Run: func(ctx context.Context) error {
+			n, err := repository.PurgeSessions(tokenRetention)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				log.Printf("🧹 %d sesiones eliminadas", n)
+			}
+			return nil
+		}},
+		{Name: "purge_email_verifications", Interval: interval, Run: func(ctx context.Context) error {
+			n, err := repository.PurgeEmailVerificationTokens(tokenRetention)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				log.Printf("🧹 %d tokens de verificación de email eliminados", n)
+			}
+			return nil
+		}},
+		{Name: "purge_event_invitations", Interval: interval, Run: func(ctx context.Context) error {
+			n, err := repository.PurgeEventInvitations(tokenRetention)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				log.Printf("🧹 %d invitaciones a equipos de eventos eliminadas", n)
+			}
+			return nil
+		}},
+	}
+}
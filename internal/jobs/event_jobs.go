@@ -0,0 +1,92 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"sport-events-backend/internal/lifecycle"
+	"sport-events-backend/internal/repository"
+	"sport-events-backend/internal/services"
+)
+
+// EventJobs devuelve los jobs del ciclo de vida de los eventos.
+// completeAfter es el margen tras la fecha del evento antes de darlo por terminado.
+func EventJobs(interval, completeAfter time.Duration) []Job {
+	return []Job{
+		{Name: "open_registrations", Interval: interval, Run: func(ctx context.Context) error {
+			return transitionAll(ctx, repository.GetEventsToOpenRegistration, lifecycle.StatusRegistrationOpen, "apertura programada de inscripciones")
+		}},
+		{Name: "close_registrations", Interval: interval, Run: func(ctx context.Context) error {
+			return transitionAll(ctx, repository.GetEventsToCloseRegistration, lifecycle.StatusRegistrationClosed, "cierre programado de inscripciones")
+		}},
+		{Name: "complete_events", Interval: interval, Run: func(ctx context.Context) error {
+			due := func() ([]int, error) { return repository.GetEventsToComplete(completeAfter) }
+			return transitionAll(ctx, due, lifecycle.StatusCompleted, "evento finalizado automáticamente")
+		}},
+		{Name: "finalize_results", Interval: interval, Run: finalizeResults},
+		{Name: "send_reminders", Interval: interval, Run: sendReminders},
+	}
+}
+
+// transitionAll aplica la transición a cada evento pendiente. Un evento que cambió de
+// estado entre la consulta y la transición se omite sin error.
+func transitionAll(ctx context.Context, due func() ([]int, error), to, reason string) error {
+	ids, err := due()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := repository.TransitionEventStatus(id, to, nil, reason); err != nil {
+			if errors.Is(err, lifecycle.ErrInvalidTransition) {
+				continue
+			}
+			log.Printf("⚠️ Evento %d → %s: %v", id, to, err)
+			continue
+		}
+		log.Printf("📅 Evento %d → %s", id, to)
+	}
+	return nil
+}
+
+// finalizeResults hace el último recálculo de resultados de los eventos terminados
+func finalizeResults(ctx context.Context) error {
+	ids, err := repository.GetEventsToFinalizeResults()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := services.RecomputeEventResults(id); err != nil {
+			log.Printf("⚠️ Resultados del evento %d: %v", id, err)
+			continue
+		}
+		if err := repository.MarkResultsFinalized(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendReminders encola los recordatorios cuya hora llegó
+func sendReminders(ctx context.Context) error {
+	ids, err := repository.GetDueReminders()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := repository.SendReminder(id); err != nil {
+			log.Printf("⚠️ Recordatorio %d: %v", id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"sport-events-backend/internal/notify"
+	"sport-events-backend/internal/repository"
+)
+
+const (
+	notificationBatch = 50
+	notificationLease = 2 * time.Minute // tiempo máximo de un envío antes de reintentar
+	maxRetryBackoff   = time.Hour
+)
+
+// NotificationJob entrega las notificaciones pendientes del outbox
+func NotificationJob(d *notify.Dispatcher, interval time.Duration) Job {
+	return Job{Name: "deliver_notifications", Interval: interval, Run: func(ctx context.Context) error {
+		return deliverNotifications(ctx, d)
+	}}
+}
+
+func deliverNotifications(ctx context.Context, d *notify.Dispatcher) error {
+	for {
+		batch, err := repository.ClaimDueNotifications(notificationBatch, notificationLease)
+		if err != nil {
+			return err
+		}
+		for _, n := range batch {
+			if err := d.Deliver(ctx, n); err != nil {
+				if err := repository.MarkNotificationFailed(n, err, retryBackoff(n.Attempts)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := repository.MarkNotificationSent(n.ID); err != nil {
+				return err
+			}
+		}
+		if len(batch) < notificationBatch || ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// retryBackoff: 1, 2, 4, 8... minutos, con tope de una hora
+func retryBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 6 {
+		return maxRetryBackoff
+	}
+	d := time.Minute << uint(attempts-1)
+	if d > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return d
+}
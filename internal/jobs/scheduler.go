@@ -0,0 +1,65 @@
+// Package jobs ejecuta tareas periódicas dentro del proceso de la API.
+// Cada ejecución toma un advisory lock de Postgres, así que con varias réplicas
+// solo una corre cada job a la vez.
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"sport-events-backend/internal/repository"
+)
+
+// Job es una tarea periódica; Run debe ser idempotente
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	jobs []Job
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start lanza una goroutine por job; se detienen al cancelar ctx
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		go s.loop(ctx, job)
+	}
+	log.Printf("⏱️ Scheduler iniciado con %d jobs", len(s.jobs))
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		runOnce(ctx, job)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runOnce(ctx context.Context, job Job) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("⚠️ Job %s: panic: %v", job.Name, rec)
+		}
+	}()
+
+	if _, err := repository.WithJobLock(job.Name, func() error { return job.Run(ctx) }); err != nil {
+		log.Printf("⚠️ Job %s: %v", job.Name, err)
+	}
+}
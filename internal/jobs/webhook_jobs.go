@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"sport-events-backend/internal/repository"
+	"sport-events-backend/internal/webhooks"
+)
+
+const (
+	webhookBatch = 50
+	webhookLease = time.Minute
+)
+
+// WebhookJob entrega los webhooks pendientes de los organizadores
+func WebhookJob(s webhooks.Sender, interval time.Duration) Job {
+	return Job{Name: "deliver_webhooks", Interval: interval, Run: func(ctx context.Context) error {
+		return deliverWebhooks(ctx, s)
+	}}
+}
+
+func deliverWebhooks(ctx context.Context, s webhooks.Sender) error {
+	for {
+		batch, err := repository.ClaimDueWebhookDeliveries(webhookBatch, webhookLease)
+		if err != nil {
+			return err
+		}
+		for _, d := range batch {
+			status, err := s.Send(ctx, d)
+			if err != nil {
+				if err := repository.MarkWebhookFailed(d.WebhookDelivery, status, err, retryBackoff(d.Attempts)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := repository.MarkWebhookDelivered(d.ID, status); err != nil {
+				return err
+			}
+		}
+		if len(batch) < webhookBatch || ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
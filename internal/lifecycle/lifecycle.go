@@ -0,0 +1,108 @@
+// Package lifecycle define los estados de un evento y las transiciones permitidas.
+// Es el único lugar donde se decide qué se puede hacer en cada estado.
+package lifecycle
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Estados del evento
+const (
+	StatusDraft              = "draft"
+	StatusPublished          = "published"
+	StatusRegistrationOpen   = "registration_open"
+	StatusRegistrationClosed = "registration_closed"
+	StatusLive               = "live"
+	StatusCompleted          = "completed"
+	StatusCancelled          = "cancelled"
+	StatusPostponed          = "postponed"
+)
+
+// Estado inicial de los eventos nuevos
+const InitialStatus = StatusDraft
+
+var (
+	ErrUnknownStatus      = errors.New("estado de evento desconocido")
+	ErrInvalidTransition  = errors.New("transición de estado no permitida")
+	ErrRegistrationClosed = errors.New("las inscripciones del evento no están abiertas")
+	ErrCheckinNotAllowed  = errors.New("el evento no está en curso")
+	ErrEventLocked        = errors.New("el evento ya no se puede modificar")
+)
+
+// transitions: estado actual → estados a los que puede pasar
+var transitions = map[string][]string{
+	StatusDraft:              {StatusPublished, StatusCancelled},
+	StatusPublished:          {StatusDraft, StatusRegistrationOpen, StatusPostponed, StatusCancelled},
+	StatusRegistrationOpen:   {StatusRegistrationClosed, StatusPostponed, StatusCancelled},
+	StatusRegistrationClosed: {StatusRegistrationOpen, StatusLive, StatusCompleted, StatusPostponed, StatusCancelled},
+	StatusLive:               {StatusCompleted, StatusCancelled},
+	StatusPostponed:          {StatusPublished, StatusRegistrationOpen, StatusCancelled},
+	StatusCompleted:          {},
+	StatusCancelled:          {},
+}
+
+// Valid indica si el estado existe
+func Valid(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// Next devuelve los estados a los que se puede pasar desde el actual
+func Next(from string) []string {
+	next := make([]string, len(transitions[from]))
+	copy(next, transitions[from])
+	return next
+}
+
+// CanTransition valida el cambio de estado
+func CanTransition(from, to string) error {
+	if !Valid(from) || !Valid(to) {
+		return ErrUnknownStatus
+	}
+	for _, s := range transitions[from] {
+		if s == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s → %s", ErrInvalidTransition, from, to)
+}
+
+// CheckRegistration: solo se aceptan inscripciones con el registro abierto
+func CheckRegistration(status string) error {
+	if status != StatusRegistrationOpen {
+		return ErrRegistrationClosed
+	}
+	return nil
+}
+
+// CheckRegistrationCancel: el corredor puede darse de baja hasta que empieza la carrera
+func CheckRegistrationCancel(status string) error {
+	switch status {
+	case StatusLive, StatusCompleted, StatusCancelled:
+		return ErrEventLocked
+	}
+	return nil
+}
+
+// CheckCheckin: los checkins solo se registran con la carrera en curso
+func CheckCheckin(status string) error {
+	if status != StatusLive {
+		return ErrCheckinNotAllowed
+	}
+	return nil
+}
+
+// CheckEdit: los eventos terminados o cancelados no se editan
+func CheckEdit(status string) error {
+	switch status {
+	case StatusCompleted, StatusCancelled:
+		return ErrEventLocked
+	}
+	return nil
+}
+
+// IsPublic indica si el evento es visible para usuarios que no son el organizador
+func IsPublic(status string) bool {
+	return status != StatusDraft
+}
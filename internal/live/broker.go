@@ -0,0 +1,71 @@
+// Package live reparte en memoria los checkins recién registrados a los clientes
+// suscritos (stream SSE) de cada evento.
+package live
+
+import (
+	"sync"
+
+	"sport-events-backend/internal/models"
+)
+
+const subscriberBuffer = 64
+
+// Subscription es la suscripción de un cliente a un evento.
+// Si el cliente no consume a tiempo se cierra Overflow: debe desconectarse y
+// reconectar con Last-Event-ID para recuperar lo perdido desde la BD.
+type Subscription struct {
+	C        <-chan models.LiveCheckin
+	Overflow <-chan struct{}
+
+	ch       chan models.LiveCheckin
+	overflow chan struct{}
+	once     sync.Once
+}
+
+type Broker struct {
+	mu   sync.RWMutex
+	subs map[int]map[*Subscription]struct{}
+}
+
+// Default es el broker del proceso, usado por los handlers
+var Default = NewBroker()
+
+func NewBroker() *Broker {
+	return &Broker{subs: map[int]map[*Subscription]struct{}{}}
+}
+
+// Subscribe registra un cliente para el evento; llamar a la función devuelta al terminar
+func (b *Broker) Subscribe(eventID int) (*Subscription, func()) {
+	ch := make(chan models.LiveCheckin, subscriberBuffer)
+	overflow := make(chan struct{})
+	sub := &Subscription{C: ch, Overflow: overflow, ch: ch, overflow: overflow}
+
+	b.mu.Lock()
+	if b.subs[eventID] == nil {
+		b.subs[eventID] = map[*Subscription]struct{}{}
+	}
+	b.subs[eventID][sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub, func() {
+		b.mu.Lock()
+		delete(b.subs[eventID], sub)
+		if len(b.subs[eventID]) == 0 {
+			delete(b.subs, eventID)
+		}
+		b.mu.Unlock()
+	}
+}
+
+// Publish envía el checkin a todos los suscriptores del evento sin bloquear
+func (b *Broker) Publish(msg models.LiveCheckin) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subs[msg.EventID] {
+		select {
+		case sub.ch <- msg:
+		default:
+			sub.once.Do(func() { close(sub.overflow) })
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Package mailer envía emails a partir de plantillas (texto + HTML) localizadas.
+// Backends: SMTP (probado contra MailHog) y "log" para desarrollo.
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	ErrSMTPNotConfigured = errors.New("SMTP_HOST no configurado (usa MAIL_BACKEND=log para no enviar emails)")
+	ErrNotInitialized    = errors.New("mailer no inicializado")
+)
+
+// Message es un email ya renderizado
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Backend entrega el mensaje
+type Backend interface {
+	Send(ctx context.Context, from string, msg Message) error
+}
+
+type Mailer struct {
+	backend Backend
+	from    string
+}
+
+func New(backend Backend, from string) *Mailer {
+	return &Mailer{backend: backend, from: from}
+}
+
+// FromEnv configura el backend con MAIL_BACKEND (smtp | log, por defecto smtp).
+// El backend de log (no envía nada) hay que pedirlo explícitamente: con smtp y sin
+// SMTP_HOST devuelve ErrSMTPNotConfigured, para no perder emails en silencio.
+func FromEnv() (*Mailer, error) {
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = "no-reply@sport-events.local"
+	}
+
+	switch backend := os.Getenv("MAIL_BACKEND"); backend {
+	case "", "smtp":
+		if os.Getenv("SMTP_HOST") == "" {
+			return nil, ErrSMTPNotConfigured
+		}
+		return New(SMTPBackendFromEnv(), from), nil
+	case "log":
+		return New(LogBackend{}, from), nil
+	default:
+		return nil, fmt.Errorf("MAIL_BACKEND desconocido: %q (smtp | log)", backend)
+	}
+}
+
+var defaultMailer *Mailer
+
+// Init configura el mailer por defecto a partir del entorno (ver FromEnv).
+// Se llama al arrancar para que una configuración incompleta falle enseguida.
+func Init() error {
+	m, err := FromEnv()
+	if err != nil {
+		return err
+	}
+	defaultMailer = m
+	return nil
+}
+
+// Default devuelve el mailer configurado con Init (nil si no se inicializó)
+func Default() *Mailer {
+	return defaultMailer
+}
+
+func (m *Mailer) Send(ctx context.Context, msg Message) error {
+	if m == nil {
+		return ErrNotInitialized
+	}
+	return m.backend.Send(ctx, m.from, msg)
+}
+
+// SendTemplate renderiza la plantilla en el idioma pedido (o español) y la envía
+func (m *Mailer) SendTemplate(ctx context.Context, to, name, locale string, data map[string]interface{}) error {
+	msg, err := Render(name, locale, data)
+	if err != nil {
+		return err
+	}
+	msg.To = to
+	return m.Send(ctx, msg)
+}
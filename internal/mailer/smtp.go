@@ -0,0 +1,157 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"time"
+)
+
+// smtpTimeout limita la conversación SMTP cuando el contexto no trae deadline
+const smtpTimeout = 30 * time.Second
+
+// SMTPBackend envía por SMTP. Sin usuario no autentica (MailHog en localhost:1025).
+type SMTPBackend struct {
+	Addr string
+	Auth smtp.Auth
+}
+
+func SMTPBackendFromEnv() SMTPBackend {
+	host := os.Getenv("SMTP_HOST")
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	b := SMTPBackend{Addr: host + ":" + port}
+	if user := os.Getenv("SMTP_USER"); user != "" {
+		b.Auth = smtp.PlainAuth("", user, os.Getenv("SMTP_PASSWORD"), host)
+	}
+	return b
+}
+
+func (b SMTPBackend) Send(ctx context.Context, from string, msg Message) error {
+	raw, err := buildMIME(from, msg)
+	if err != nil {
+		return err
+	}
+	if err := b.send(ctx, from, msg.To, raw); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err() // la conexión se cerró por el contexto
+		}
+		return err
+	}
+	return nil
+}
+
+// send es smtp.SendMail respetando ctx: la conexión se abre con DialContext, lleva el
+// deadline del contexto y se cierra si el contexto se cancela a mitad del envío.
+func (b SMTPBackend) send(ctx context.Context, from, to string, raw []byte) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", b.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	host, _, err := net.SplitHostPort(b.Addr)
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if b.Auth != nil {
+		if err := c.Auth(b.Auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	wc, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := wc.Write(raw); err != nil {
+		return err
+	}
+	if err := wc.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// buildMIME arma un multipart/alternative con la parte de texto y la HTML
+func buildMIME(from string, msg Message) ([]byte, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	parts := []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", msg.Text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	}
+	for _, p := range parts {
+		if p.content == "" {
+			continue
+		}
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"8bit"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := pw.Write([]byte(p.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "From: %s\r\n", from)
+	fmt.Fprintf(&out, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&out, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&out, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&out, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&out, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", w.Boundary())
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
+
+// LogBackend no envía nada: registra destinatario y asunto (modo "dry-run" para desarrollo).
+// El cuerpo no se registra porque puede llevar tokens en claro.
+type LogBackend struct{}
+
+func (LogBackend) Send(ctx context.Context, from string, msg Message) error {
+	log.Printf("✉️ [mail dry-run] para %s: %s", msg.To, msg.Subject)
+	return nil
+}
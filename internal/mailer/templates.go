@@ -0,0 +1,108 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Cada plantilla es templates/<locale>/<nombre>.tmpl y define los bloques
+// "subject", "text" y "html". Los datos llegan como mapa (el payload de la notificación).
+//
+//go:embed templates
+var templateFS embed.FS
+
+const DefaultLocale = "es"
+
+// Locales disponibles
+var Locales = []string{"es", "en"}
+
+func SupportedLocale(locale string) bool {
+	for _, l := range Locales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Render ejecuta la plantilla en el idioma pedido; si no existe, usa español
+func Render(name, locale string, data map[string]interface{}) (Message, error) {
+	if !SupportedLocale(locale) {
+		locale = DefaultLocale
+	}
+	src, err := templateFS.ReadFile("templates/" + locale + "/" + name + ".tmpl")
+	if err != nil && locale != DefaultLocale {
+		locale = DefaultLocale
+		src, err = templateFS.ReadFile("templates/" + locale + "/" + name + ".tmpl")
+	}
+	if err != nil {
+		return Message{}, fmt.Errorf("plantilla %q no encontrada", name)
+	}
+
+	vars := map[string]interface{}{}
+	for k, v := range data {
+		vars[k] = v
+	}
+	vars["locale"] = locale
+	funcs := map[string]interface{}{
+		"date": func(v interface{}) string { return formatDate(v, locale) },
+	}
+
+	textTpl, err := template.New(name).Funcs(funcs).Parse(string(src))
+	if err != nil {
+		return Message{}, err
+	}
+	htmlTpl, err := htmltemplate.New(name).Funcs(funcs).Parse(string(src))
+	if err != nil {
+		return Message{}, err
+	}
+
+	var msg Message
+	var buf bytes.Buffer
+	if err := textTpl.ExecuteTemplate(&buf, "subject", vars); err != nil {
+		return Message{}, err
+	}
+	msg.Subject = strings.TrimSpace(buf.String())
+	buf.Reset()
+	if err := textTpl.ExecuteTemplate(&buf, "text", vars); err != nil {
+		return Message{}, err
+	}
+	msg.Text = strings.TrimSpace(buf.String()) + "\n"
+	buf.Reset()
+	if err := htmlTpl.ExecuteTemplate(&buf, "html", vars); err != nil {
+		return Message{}, err
+	}
+	msg.HTML = buf.String()
+	return msg, nil
+}
+
+// formatDate acepta time.Time o el string RFC 3339 que queda tras pasar por JSON
+func formatDate(v interface{}, locale string) string {
+	var t time.Time
+	switch d := v.(type) {
+	case time.Time:
+		t = d
+	case *time.Time:
+		if d == nil {
+			return ""
+		}
+		t = *d
+	case string:
+		parsed, err := time.Parse(time.RFC3339, d)
+		if err != nil {
+			return d
+		}
+		t = parsed
+	default:
+		return ""
+	}
+	if locale == "en" {
+		return t.Format("Jan 2, 2006 3:04 PM")
+	}
+	return t.Format("02/01/2006 15:04")
+}
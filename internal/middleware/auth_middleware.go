@@ -6,17 +6,13 @@ import (
 	"os"
 	"strings"
 	"strconv"
-	"log"
 
-	"github.com/dgrijalva/jwt-go"
+	"sport-events-backend/internal/auth"
+	"sport-events-backend/internal/repository"
 )
 
-type Claims struct {
-	UserID int    `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
-	jwt.StandardClaims
-}
+// Claims son las del access token (ver auth.Claims)
+type Claims = auth.Claims
 // getEnvAsInt obtiene una variable de entorno como entero
 func getEnvAsInt(name string, defaultVal int) int {
 	valStr := os.Getenv(name)
@@ -45,32 +41,29 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			http.Error(w, "Authorization header format must be: Bearer {token}", http.StatusUnauthorized)
 			return
 		}
-		secret := os.Getenv("JWT_SECRET")
-		if secret == "" {
-			log.Println("⚠️ JWT_SECRET no está seteado en el middleware")
-		} else {
-			log.Println("🔑 JWT_SECRET cargado en middleware:", secret)
+		claims, err := auth.ParseAccessToken(parts[1])
+		if err != nil {
+			http.Error(w, "Token inválido: "+err.Error(), http.StatusUnauthorized)
+			return
 		}
 
-
-		tokenStr := parts[1]
-		claims := &Claims{}
-		var token *jwt.Token
-		var err error
-		// Parse token using the JWT secret available at request time.
-		// Handle error and invalid token separately to avoid calling err.Error() when err is nil.
-		// 'secret' was already read above and is reused here.
-		token, err = jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(secret), nil
-		})
+		// El token debe pertenecer a una sesión vigente (logout, "cerrar todas", el
+		// restablecimiento de contraseña y la suspensión de la cuenta revocan la sesión)
+		if claims.SessionID == 0 {
+			http.Error(w, "Sesión inválida; inicia sesión de nuevo", http.StatusUnauthorized)
+			return
+		}
+		role, active, err := repository.GetSessionRole(claims.SessionID, claims.UserID)
 		if err != nil {
-			http.Error(w, "Token inválido: "+err.Error(), http.StatusUnauthorized)
+			http.Error(w, "Error verificando la sesión", http.StatusInternalServerError)
 			return
 		}
-		if !token.Valid {
-			http.Error(w, "Token inválido", http.StatusUnauthorized)
+		if !active {
+			http.Error(w, "Sesión revocada o expirada", http.StatusUnauthorized)
 			return
 		}
+		// El rol se toma de la base: un cambio de rol aplica sin esperar a que venza el token
+		claims.Role = role
 
 		ctx := context.WithValue(r.Context(), ContextUserKey, claims)
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -82,21 +75,3 @@ func GetClaims(r *http.Request) (*Claims, bool) {
 	c, ok := r.Context().Value(ContextUserKey).(*Claims)
 	return c, ok
 }
-func authorizeRole(next http.HandlerFunc, roles ...string) http.HandlerFunc {
-    return func(w http.ResponseWriter, r *http.Request) {
-        claims, ok := r.Context().Value("claims").(*Claims)
-        if !ok {
-            http.Error(w, "Unauthorized", http.StatusUnauthorized)
-            return
-        }
-
-        for _, role := range roles {
-            if claims.Role == role {
-                next.ServeHTTP(w, r)
-                return
-            }
-        }
-
-        http.Error(w, "Forbidden", http.StatusForbidden)
-    }
-}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sport-events-backend/internal/lifecycle"
+	"sport-events-backend/internal/policy"
+	"sport-events-backend/internal/repository"
+)
+
+// Subject arma el sujeto de las decisiones de policy a partir de las claims
+func Subject(claims *Claims) policy.Subject {
+	return policy.Subject{UserID: claims.UserID, Role: claims.Role}
+}
+
+// RequirePermission verifica que el rol del usuario tenga el permiso
+// (y el email verificado si el permiso lo exige).
+func RequirePermission(p policy.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetClaims(r)
+			if !ok {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if !policy.Allows(claims.Role, p) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			if policy.RequiresVerifiedEmail(p) {
+				RequireVerifiedEmail(next).ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireEventPermission verifica el permiso sobre el evento de la ruta ({id}).
+// Un evento inexistente responde 403 igual que uno ajeno.
+func RequireEventPermission(p policy.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetClaims(r)
+			if !ok {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			eventID, err := strconv.Atoi(mux.Vars(r)["id"])
+			if err != nil {
+				http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+				return
+			}
+			allowed, err := policy.CanOnEvent(Subject(claims), eventID, p)
+			if err != nil {
+				http.Error(w, "Error verificando permisos: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !allowed {
+				http.Error(w, "No tienes permiso sobre este evento", http.StatusForbidden)
+				return
+			}
+			if policy.RequiresVerifiedEmail(p) {
+				RequireVerifiedEmail(next).ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireEventVisible deja ver el evento de la ruta ({id}) si es público o si el usuario
+// tiene event:view (los borradores solo los ve el equipo). Si no, responde 404 como
+// si no existiera, igual que el detalle del evento.
+func RequireEventVisible(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := GetClaims(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		eventID, err := strconv.Atoi(mux.Vars(r)["id"])
+		if err != nil {
+			http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+			return
+		}
+		status, err := repository.GetEventStatus(eventID)
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Evento no encontrado", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Error obteniendo evento: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !lifecycle.IsPublic(status) {
+			allowed, err := policy.CanOnEvent(Subject(claims), eventID, policy.EventView)
+			if err != nil {
+				http.Error(w, "Error verificando permisos: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !allowed {
+				http.Error(w, "Evento no encontrado", http.StatusNotFound)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireVerifiedEmail responde 403 si el usuario aún no confirmó su email
+func RequireVerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := GetClaims(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		verified, err := repository.IsEmailVerified(claims.UserID)
+		if err != nil {
+			http.Error(w, "Error verificando el usuario", http.StatusInternalServerError)
+			return
+		}
+		if !verified {
+			http.Error(w, "Debes verificar tu email antes de continuar", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
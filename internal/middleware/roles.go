@@ -1,25 +0,0 @@
-package middleware
-
-import "net/http"
-
-// RoleMiddleware verifica que el usuario en contexto tenga uno de los roles permitidos.
-func RoleMiddleware(allowedRoles ...string) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			claims, ok := r.Context().Value(ContextUserKey).(*Claims)
-			if !ok {
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
-				return
-			}
-
-			for _, role := range allowedRoles {
-				if claims.Role == role {
-					next.ServeHTTP(w, r)
-					return
-				}
-			}
-
-			http.Error(w, "Forbidden", http.StatusForbidden)
-		})
-	}
-}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sport-events-backend/internal/auth"
+	"sport-events-backend/internal/repository"
+)
+
+// StreamAuthMiddleware autentica los streams SSE, que desde el navegador (EventSource) no
+// pueden enviar headers: acepta ?stream_token= emitido para el evento de la ruta ({id}).
+// Sin stream_token se comporta como AuthMiddleware (header Authorization).
+func StreamAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := r.URL.Query().Get("stream_token")
+		if raw == "" {
+			AuthMiddleware(next).ServeHTTP(w, r)
+			return
+		}
+		eventID, err := strconv.Atoi(mux.Vars(r)["id"])
+		if err != nil {
+			http.Error(w, "ID de evento inválido", http.StatusBadRequest)
+			return
+		}
+		stream, err := auth.ParseStreamToken(raw, eventID)
+		if err != nil {
+			http.Error(w, "Token de stream inválido: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		// Igual que con el access token, la sesión de origen debe seguir vigente
+		role, active, err := repository.GetSessionRole(stream.SessionID, stream.UserID)
+		if err != nil {
+			http.Error(w, "Error verificando la sesión", http.StatusInternalServerError)
+			return
+		}
+		if !active {
+			http.Error(w, "Sesión revocada o expirada", http.StatusUnauthorized)
+			return
+		}
+
+		claims := &Claims{UserID: stream.UserID, Role: role, SessionID: stream.SessionID}
+		ctx := context.WithValue(r.Context(), ContextUserKey, claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Géneros admitidos en el perfil del corredor
+const (
+	GenderFemale = "female"
+	GenderMale   = "male"
+	GenderOther  = "other"
+)
+
+func ValidGender(g string) bool {
+	return g == GenderFemale || g == GenderMale || g == GenderOther
+}
+
+// AgeGroup es un rango de edad (cumplida el día de la carrera) para las premiaciones
+type AgeGroup struct {
+	Name   string `json:"name"` // si viene vacío se genera ("30-39", "60+")
+	MinAge int    `json:"min_age"`
+	MaxAge *int   `json:"max_age,omitempty"` // nil = sin tope
+}
+
+// AgeGroups se guarda como JSONB en events.age_groups (NULL = DefaultAgeGroups)
+type AgeGroups []AgeGroup
+
+func intPtr(v int) *int { return &v }
+
+// DefaultAgeGroups se usa cuando el evento no define sus propios grupos
+var DefaultAgeGroups = AgeGroups{
+	{Name: "U18", MinAge: 0, MaxAge: intPtr(17)},
+	{Name: "18-29", MinAge: 18, MaxAge: intPtr(29)},
+	{Name: "30-39", MinAge: 30, MaxAge: intPtr(39)},
+	{Name: "40-49", MinAge: 40, MaxAge: intPtr(49)},
+	{Name: "50-59", MinAge: 50, MaxAge: intPtr(59)},
+	{Name: "60+", MinAge: 60},
+}
+
+func (g AgeGroups) Value() (driver.Value, error) {
+	if g == nil {
+		return nil, nil
+	}
+	return json.Marshal(g)
+}
+
+func (g *AgeGroups) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, g)
+	case string:
+		return json.Unmarshal([]byte(v), g)
+	case nil:
+		*g = nil
+		return nil
+	}
+	return errors.New("age_groups: tipo no soportado")
+}
+
+// Normalize ordena los grupos por edad mínima y completa los nombres vacíos
+func (g AgeGroups) Normalize() AgeGroups {
+	out := make(AgeGroups, len(g))
+	copy(out, g)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].MinAge < out[j].MinAge })
+	for i := range out {
+		if out[i].Name != "" {
+			continue
+		}
+		if out[i].MaxAge == nil {
+			out[i].Name = fmt.Sprintf("%d+", out[i].MinAge)
+		} else {
+			out[i].Name = fmt.Sprintf("%d-%d", out[i].MinAge, *out[i].MaxAge)
+		}
+	}
+	return out
+}
+
+// Validate revisa que los rangos sean coherentes y no se solapen
+func (g AgeGroups) Validate() ValidationErrors {
+	var errs ValidationErrors
+	names := map[string]bool{}
+	for i, grp := range g {
+		field := fmt.Sprintf("age_groups[%d]", i)
+		if grp.MinAge < 0 {
+			errs.add(field+".min_age", "no puede ser negativo")
+		}
+		if grp.MaxAge != nil && *grp.MaxAge < grp.MinAge {
+			errs.add(field+".max_age", "debe ser mayor o igual que min_age")
+		}
+		if grp.Name != "" {
+			if names[grp.Name] {
+				errs.add(field+".name", "nombre %q repetido", grp.Name)
+			}
+			names[grp.Name] = true
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	sorted := g.Normalize()
+	for i := 1; i < len(sorted); i++ {
+		prev := sorted[i-1]
+		if prev.MaxAge == nil || *prev.MaxAge >= sorted[i].MinAge {
+			errs.add("age_groups", "los grupos %q y %q se solapan", prev.Name, sorted[i].Name)
+		}
+	}
+	return errs
+}
+
+// Find devuelve el grupo que contiene la edad
+func (g AgeGroups) Find(age int) (AgeGroup, bool) {
+	for _, grp := range g {
+		if age >= grp.MinAge && (grp.MaxAge == nil || age <= *grp.MaxAge) {
+			return grp, true
+		}
+	}
+	return AgeGroup{}, false
+}
+
+// AgeOn calcula la edad cumplida en la fecha indicada (el día de la carrera)
+func AgeOn(birthdate, day time.Time) int {
+	age := day.Year() - birthdate.Year()
+	if day.Month() < birthdate.Month() || (day.Month() == birthdate.Month() && day.Day() < birthdate.Day()) {
+		age--
+	}
+	return age
+}
+
+// AgeGroupResult es un resultado con los datos del corredor necesarios para clasificarlo
+type AgeGroupResult struct {
+	RunnerResult
+	Gender    *string    `db:"gender" json:"gender,omitempty"`
+	Birthdate *time.Time `db:"birthdate" json:"-"`
+	Age       *int       `db:"-" json:"age,omitempty"`
+	GroupRank int        `db:"-" json:"group_rank"`
+}
+
+// AgeGroupRanking es el podio de una combinación categoría + género + grupo de edad
+type AgeGroupRanking struct {
+	CategoryID   *int             `json:"category_id,omitempty"`
+	CategoryName *string          `json:"category_name,omitempty"`
+	Gender       string           `json:"gender"`
+	AgeGroup     string           `json:"age_group"`
+	Results      []AgeGroupResult `json:"results"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// EventCategory es una distancia/categoría dentro de un evento (5K, 10K, 21K...).
+// Los campos nil heredan el valor del evento (ruta, hora de salida, rango de dorsales).
+type EventCategory struct {
+	ID            int        `db:"id" json:"id"`
+	EventID       int        `db:"event_id" json:"event_id"`
+	Name          string     `db:"name" json:"name"`
+	DistanceM     *float64   `db:"distance_m" json:"distance_m,omitempty"`
+	Route         *Route     `db:"route" json:"route,omitempty"` // JSONB
+	Capacity      *int       `db:"capacity" json:"capacity,omitempty"`
+	StartTime     *time.Time `db:"start_time" json:"start_time,omitempty"`
+	Price         *float64   `db:"price" json:"price,omitempty"`
+	BibRangeStart *int       `db:"bib_range_start" json:"bib_range_start,omitempty"`
+	BibRangeEnd   *int       `db:"bib_range_end" json:"bib_range_end,omitempty"`
+	Registered    int        `db:"registered" json:"registered"` // inscritos actuales
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+}
@@ -1,7 +1,6 @@
 package models
 
 import (
-	"encoding/json"
 	"time"
 )
 
@@ -12,12 +11,24 @@ type Event struct {
 	Type        string          `db:"type" json:"type"`
 	Date        time.Time       `db:"date" json:"date"`
 	Location    string          `db:"location" json:"location"`
-	Route       json.RawMessage `db:"route" json:"route"` // JSONB
+	Route       *Route          `db:"route" json:"route"` // JSONB
 	CreatedBy   int             `db:"created_by" json:"created_by"`
 	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
-	Status            string          `db:"status" json:"status"`
+	Status            string          `db:"status" json:"status"` // ver internal/lifecycle
 	CancelledAt       *time.Time      `db:"cancelled_at" json:"cancelled_at,omitempty"`
 	CancellationReason *string        `db:"cancellation_reason" json:"cancellation_reason,omitempty"`
+	StatusChangedAt   *time.Time      `db:"status_changed_at" json:"status_changed_at,omitempty"`
+	Capacity          *int            `db:"capacity" json:"capacity,omitempty"` // nil = sin límite
+	CheckinMode       string          `db:"checkin_mode" json:"checkin_mode"`   // strict | lenient
+	CheckpointRadiusM *float64        `db:"checkpoint_radius_m" json:"checkpoint_radius_m,omitempty"` // radio por defecto de los checkpoints
+	RouteMetrics                      // distance_m, elevation_gain_m, elevation_loss_m
+	BibRangeStart     *int            `db:"bib_range_start" json:"bib_range_start,omitempty"` // nil = desde 1
+	BibRangeEnd       *int            `db:"bib_range_end" json:"bib_range_end,omitempty"`     // nil = sin tope
+	AgeGroups         AgeGroups       `db:"age_groups" json:"age_groups,omitempty"` // nil = DefaultAgeGroups
+	RegistrationOpensAt  *time.Time   `db:"registration_opens_at" json:"registration_opens_at,omitempty"`   // nil = apertura manual
+	RegistrationClosesAt *time.Time   `db:"registration_closes_at" json:"registration_closes_at,omitempty"` // nil = al empezar el evento
+	ResultsFinalizedAt   *time.Time   `db:"results_finalized_at" json:"results_finalized_at,omitempty"`
+	Categories        []EventCategory `db:"-" json:"categories,omitempty"`
 }
 type EventSummary struct {
 	ID        int       `db:"id" json:"id"`
@@ -26,6 +37,7 @@ type EventSummary struct {
 	Date      time.Time `db:"date" json:"date"`
 	Location  string    `db:"location" json:"location"`
 	CreatedBy int       `db:"created_by" json:"created_by"`
+	Status    string    `db:"status" json:"status"`
 }
 
 type RegistrationWithEvent struct {
@@ -36,11 +48,60 @@ type RegistrationWithEvent struct {
 	Date           time.Time `db:"date" json:"date"`
 	Location       string    `db:"location" json:"location"`
 	RegisteredAt   time.Time `db:"registered_at" json:"registered_at"`
+	BibNumber      *int      `db:"bib_number" json:"bib_number,omitempty"`
+	CategoryID     *int      `db:"category_id" json:"category_id,omitempty"`
+	CategoryName   *string   `db:"category_name" json:"category_name,omitempty"`
 }
 
 type EventRegistrationUser struct {
-	RegistrationID int    `db:"registration_id" json:"registration_id"`
-	UserID         int    `db:"user_id" json:"user_id"`
-	UserName       string `db:"user_name" json:"user_name"`
-	UserEmail      string `db:"user_email" json:"user_email"`
+	RegistrationID int     `db:"registration_id" json:"registration_id"`
+	UserID         int     `db:"user_id" json:"user_id"`
+	UserName       string  `db:"user_name" json:"user_name"`
+	UserEmail      string  `db:"user_email" json:"user_email"`
+	BibNumber      *int    `db:"bib_number" json:"bib_number,omitempty"`
+	CategoryID     *int    `db:"category_id" json:"category_id,omitempty"`
+	CategoryName   *string `db:"category_name" json:"category_name,omitempty"`
+}
+
+// Resultado de una inscripción: inscrito directamente o en lista de espera
+type RegistrationResult struct {
+	Status   string `json:"status"`             // registered | waitlisted
+	Position int    `json:"position,omitempty"` // posición en la lista de espera (1 = siguiente)
+	BibNumber *int  `json:"bib_number,omitempty"`
+	CategoryID *int `json:"category_id,omitempty"`
+}
+
+const (
+	RegistrationStatusRegistered = "registered"
+	RegistrationStatusWaitlisted = "waitlisted"
+)
+
+// Configuración de validación de checkins de un evento
+type EventCheckinSettings struct {
+	Status            string   `db:"status"`
+	CheckinMode       string   `db:"checkin_mode"`
+	CheckpointRadiusM *float64 `db:"checkpoint_radius_m"`
+}
+
+// Asignación de staff a un evento (y opcionalmente a un checkpoint)
+type EventStaff struct {
+	ID           int       `db:"id" json:"id"`
+	EventID      int       `db:"event_id" json:"event_id"`
+	UserID       int       `db:"user_id" json:"user_id"`
+	UserName     string    `db:"user_name" json:"user_name"`
+	UserEmail    string    `db:"user_email" json:"user_email"`
+	CheckpointID *int      `db:"checkpoint_id" json:"checkpoint_id,omitempty"` // nil = todos
+	AssignedBy   int       `db:"assigned_by" json:"assigned_by"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+// Cambio de estado de un evento (historial de la máquina de estados)
+type EventStatusTransition struct {
+	ID         int       `db:"id" json:"id"`
+	EventID    int       `db:"event_id" json:"event_id"`
+	FromStatus string    `db:"from_status" json:"from_status"`
+	ToStatus   string    `db:"to_status" json:"to_status"`
+	ChangedBy  *int      `db:"changed_by" json:"changed_by,omitempty"` // nil = sistema
+	Reason     *string   `db:"reason" json:"reason,omitempty"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
 }
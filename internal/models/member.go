@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Roles dentro del equipo de un evento
+const (
+	MemberOwner       = "owner"
+	MemberCoOrganizer = "co_organizer"
+	MemberViewer      = "viewer"
+	MemberStaff       = "staff"
+)
+
+// InvitableMemberRole: roles que se pueden asignar por invitación (el owner es el creador)
+func InvitableMemberRole(role string) bool {
+	return role == MemberCoOrganizer || role == MemberViewer || role == MemberStaff
+}
+
+// EventMember es un integrante del equipo del evento
+type EventMember struct {
+	ID        int       `db:"id" json:"id"`
+	EventID   int       `db:"event_id" json:"event_id"`
+	UserID    int       `db:"user_id" json:"user_id"`
+	UserName  string    `db:"user_name" json:"user_name"`
+	UserEmail string    `db:"user_email" json:"user_email"`
+	Role      string    `db:"role" json:"role"`
+	AddedBy   *int      `db:"added_by" json:"added_by,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// EventInvitation es una invitación pendiente (o ya aceptada) al equipo del evento
+type EventInvitation struct {
+	ID         int        `db:"id" json:"id"`
+	EventID    int        `db:"event_id" json:"event_id"`
+	EventName  string     `db:"event_name" json:"event_name,omitempty"`
+	Email      string     `db:"email" json:"email"`
+	Role       string     `db:"role" json:"role"`
+	InvitedBy  int        `db:"invited_by" json:"invited_by"`
+	ExpiresAt  time.Time  `db:"expires_at" json:"expires_at"`
+	AcceptedAt *time.Time `db:"accepted_at" json:"accepted_at,omitempty"`
+	AcceptedBy *int       `db:"accepted_by" json:"accepted_by,omitempty"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+}
+
+// MemberEvent es un evento del que el usuario forma parte del equipo
+type MemberEvent struct {
+	EventSummary
+	MemberRole string `db:"member_role" json:"member_role"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Canales de entrega de notificaciones
+const (
+	NotificationChannelEmail   = "email"
+	NotificationChannelWebhook = "webhook"
+	NotificationChannelInbox   = "inbox" // bandeja en la app; no pasa por el worker
+)
+
+// Tipos de notificación
+const (
+	NotificationEventCancelled = "event_cancelled"
+	NotificationEventPostponed = "event_postponed"
+	NotificationEventUpdated   = "event_updated" // cambio de fecha o lugar
+
+	NotificationRegistrationConfirmed = "registration_confirmed"
+	NotificationRaceReminder          = "race_reminder"
+)
+
+// Estados de entrega
+const (
+	NotificationStatusPending = "pending"
+	NotificationStatusSending = "sending"
+	NotificationStatusSent    = "sent"
+	NotificationStatusFailed  = "failed"
+)
+
+// Notification es una fila del outbox de notificaciones
+type Notification struct {
+	ID            int             `db:"id" json:"id"`
+	EventID       *int            `db:"event_id" json:"event_id,omitempty"`
+	UserID        *int            `db:"user_id" json:"user_id,omitempty"`
+	Channel       string          `db:"channel" json:"channel"`
+	Kind          string          `db:"kind" json:"kind"`
+	Recipient     string          `db:"recipient" json:"recipient"`
+	Payload       json.RawMessage `db:"payload" json:"payload"`
+	Locale        string          `db:"locale" json:"locale"`
+	Status        string          `db:"status" json:"status"`
+	Attempts      int             `db:"attempts" json:"attempts"`
+	MaxAttempts   int             `db:"max_attempts" json:"max_attempts"`
+	NextAttemptAt time.Time       `db:"next_attempt_at" json:"next_attempt_at"`
+	LastError     *string         `db:"last_error" json:"last_error,omitempty"`
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
+	SentAt        *time.Time      `db:"sent_at" json:"sent_at,omitempty"`
+	ReadAt        *time.Time      `db:"read_at" json:"read_at,omitempty"` // solo inbox
+}
+
+// RegistrationNotice es el contenido de la confirmación de inscripción
+type RegistrationNotice struct {
+	EventID      int       `db:"event_id" json:"event_id"`
+	EventName    string    `db:"event_name" json:"event_name"`
+	Date         time.Time `db:"date" json:"date"`
+	Location     string    `db:"location" json:"location"`
+	UserName     string    `db:"user_name" json:"user_name"`
+	BibNumber    *int      `db:"-" json:"bib_number,omitempty"`
+	CategoryName *string   `db:"category_name" json:"category_name,omitempty"`
+}
+
+// EventNotice es el contenido de una notificación sobre un evento
+type EventNotice struct {
+	EventID     int        `json:"event_id"`
+	EventName   string     `json:"event_name"`
+	Kind        string     `json:"kind"`
+	Date        time.Time  `json:"date"`
+	Location    string     `json:"location"`
+	OldDate     *time.Time `json:"old_date,omitempty"`
+	OldLocation *string    `json:"old_location,omitempty"`
+	Reason      *string    `json:"reason,omitempty"`
+}
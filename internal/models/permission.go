@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// EventAccess: relación de un usuario con un evento, de la que el paquete policy
+// deriva los permisos que tiene sobre él
+type EventAccess struct {
+	Exists           bool           `db:"event_exists"`
+	MemberRole       string         `db:"member_role"` // rol en el equipo del evento ("" si no es miembro)
+	Grants           pq.StringArray `db:"grants"`
+	StaffEventWide   bool           `db:"staff_event_wide"`  // staff de todos los checkpoints
+	StaffCheckpoints pq.Int64Array  `db:"staff_checkpoints"` // checkpoints asignados al staff
+}
+
+// IsStaff indica si el usuario tiene alguna asignación de staff en el evento
+func (a EventAccess) IsStaff() bool {
+	return a.StaffEventWide || len(a.StaffCheckpoints) > 0
+}
+
+// EventPermissionGrant es un permiso concedido a un usuario sobre un evento
+type EventPermissionGrant struct {
+	ID         int       `db:"id" json:"id"`
+	EventID    int       `db:"event_id" json:"event_id"`
+	UserID     int       `db:"user_id" json:"user_id"`
+	UserName   string    `db:"user_name" json:"user_name"`
+	UserEmail  string    `db:"user_email" json:"user_email"`
+	Permission string    `db:"permission" json:"permission"`
+	GrantedBy  int       `db:"granted_by" json:"granted_by"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
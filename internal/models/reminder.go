@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// EventReminder es un recordatorio que se envía offset_minutes antes del evento
+type EventReminder struct {
+	ID            int        `db:"id" json:"id"`
+	EventID       int        `db:"event_id" json:"event_id"`
+	OffsetMinutes int        `db:"offset_minutes" json:"offset_minutes"`
+	Message       *string    `db:"message" json:"message,omitempty"`
+	SentAt        *time.Time `db:"sent_at" json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+}
+
+// NotificationPreferences: qué avisos quiere recibir el usuario por email.
+// La bandeja de la app los recibe siempre.
+type NotificationPreferences struct {
+	UserID            int        `db:"user_id" json:"-"`
+	EmailReminders    bool       `db:"email_reminders" json:"email_reminders"`
+	EmailEventUpdates bool       `db:"email_event_updates" json:"email_event_updates"`
+	UpdatedAt         *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// DefaultNotificationPreferences se usa mientras el usuario no guardó las suyas
+func DefaultNotificationPreferences(userID int) NotificationPreferences {
+	return NotificationPreferences{UserID: userID, EmailReminders: true, EmailEventUpdates: true}
+}
+
+// ReminderNotice es el contenido del recordatorio para un inscrito
+type ReminderNotice struct {
+	EventID      int       `db:"event_id" json:"event_id"`
+	EventName    string    `db:"event_name" json:"event_name"`
+	Date         time.Time `db:"date" json:"date"`
+	Location     string    `db:"location" json:"location"`
+	UserName     string    `db:"user_name" json:"user_name"`
+	BibNumber    *int      `db:"bib_number" json:"bib_number,omitempty"`
+	CategoryName *string   `db:"category_name" json:"category_name,omitempty"`
+	Message      *string   `db:"-" json:"message,omitempty"`
+}
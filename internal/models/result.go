@@ -0,0 +1,80 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Estados de un resultado
+const (
+	ResultStatusFinished   = "finished"   // pasó por start y finish
+	ResultStatusRunning    = "running"    // pasó por start, aún sin finish
+	ResultStatusIncomplete = "incomplete" // tiene finish pero no start (sin tiempo válido)
+)
+
+// Split: paso por un checkpoint intermedio (o la meta) relativo a la salida
+type Split struct {
+	CheckpointID   int       `json:"checkpoint_id"`
+	CheckpointName string    `json:"checkpoint_name"`
+	At             time.Time `json:"at"`
+	ElapsedMS      int64     `json:"elapsed_ms"`            // desde la salida
+	SegmentMS      int64     `json:"segment_ms"`            // desde el checkpoint anterior
+	DistanceM      float64   `json:"distance_m"`            // acumulada desde la salida
+	SegmentPace    *float64  `json:"segment_pace_s_per_km"` // ritmo del tramo (s/km)
+}
+
+// Splits se guarda como JSONB
+type Splits []Split
+
+func (s Splits) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s)
+}
+
+func (s *Splits) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	case nil:
+		*s = nil
+		return nil
+	}
+	return errors.New("splits: tipo no soportado")
+}
+
+type RunnerResult struct {
+	EventID     int        `db:"event_id" json:"event_id"`
+	UserID      int        `db:"user_id" json:"user_id"`
+	UserName    string     `db:"user_name" json:"user_name"`
+	BibNumber   *int       `db:"bib_number" json:"bib_number,omitempty"`
+	CategoryID  *int       `db:"category_id" json:"category_id,omitempty"`
+	Category    *string    `db:"category_name" json:"category_name,omitempty"`
+	Status      string     `db:"status" json:"status"`
+	OverallRank *int       `db:"overall_rank" json:"overall_rank,omitempty"` // dentro de su categoría si el evento tiene categorías
+	StartedAt   *time.Time `db:"started_at" json:"started_at,omitempty"`
+	FinishedAt  *time.Time `db:"finished_at" json:"finished_at,omitempty"`
+	ElapsedMS   *int64     `db:"elapsed_ms" json:"elapsed_ms,omitempty"`
+	DistanceM   *float64   `db:"distance_m" json:"distance_m,omitempty"`
+	PaceSPerKm  *float64   `db:"pace_s_per_km" json:"pace_s_per_km,omitempty"`
+	Splits      Splits     `db:"splits" json:"splits"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// LiveCheckin es un checkin tal como se transmite en el stream en vivo
+type LiveCheckin struct {
+	ID             int       `db:"id" json:"id"`
+	EventID        int       `db:"event_id" json:"event_id"`
+	UserID         int       `db:"user_id" json:"user_id"`
+	UserName       string    `db:"user_name" json:"user_name"`
+	BibNumber      *int      `db:"bib_number" json:"bib_number,omitempty"`
+	CheckpointID   int       `db:"checkpoint_id" json:"checkpoint_id"`
+	CheckpointName string    `db:"-" json:"checkpoint_name"`
+	Timestamp      time.Time `db:"created_at" json:"timestamp"`
+	Position       int       `db:"position" json:"position"` // orden de llegada a ese checkpoint
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Roles de usuario
+const (
+	RoleRunner    = "runner"
+	RoleOrganizer = "organizer"
+	RoleAdmin     = "admin"
+)
+
+func ValidRole(role string) bool {
+	return role == RoleRunner || role == RoleOrganizer || role == RoleAdmin
+}
+
+// Estados de una solicitud de organizador
+const (
+	ApplicationPending  = "pending"
+	ApplicationApproved = "approved"
+	ApplicationRejected = "rejected"
+)
+
+// OrganizerApplication es la solicitud de un runner para pasar a organizador
+type OrganizerApplication struct {
+	ID           int        `db:"id" json:"id"`
+	UserID       int        `db:"user_id" json:"user_id"`
+	UserName     string     `db:"user_name" json:"user_name,omitempty"`
+	UserEmail    string     `db:"user_email" json:"user_email,omitempty"`
+	Organization *string    `db:"organization" json:"organization,omitempty"`
+	Message      *string    `db:"message" json:"message,omitempty"`
+	Status       string     `db:"status" json:"status"`
+	ReviewedBy   *int       `db:"reviewed_by" json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time `db:"reviewed_at" json:"reviewed_at,omitempty"`
+	ReviewNote   *string    `db:"review_note" json:"review_note,omitempty"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+}
+
+// UserFilter: búsqueda de usuarios del panel de administración
+type UserFilter struct {
+	Query     string // nombre o email (contiene)
+	Role      string
+	Suspended *bool
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tipos de checkpoint dentro de la ruta
+const (
+	CheckpointTypeStart        = "start"
+	CheckpointTypeFinish       = "finish"
+	CheckpointTypeIntermediate = "checkpoint"
+)
+
+// Modos de validación del orden de checkins
+const (
+	CheckinModeStrict  = "strict"
+	CheckinModeLenient = "lenient"
+)
+
+// Route es el recorrido de un evento (se guarda como JSONB en events.route)
+type Route struct {
+	Polyline    []RoutePoint           `json:"polyline"`
+	Checkpoints []Checkpoint           `json:"checkpoints"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// RoutePoint es un vértice del trazado; la elevación es opcional
+type RoutePoint struct {
+	Lat float64  `json:"lat"`
+	Lng float64  `json:"lng"`
+	Ele *float64 `json:"ele,omitempty"`
+}
+
+type Checkpoint struct {
+	ID    int     `json:"id"`
+	Name  string  `json:"name"`
+	Lat   float64 `json:"lat"`
+	Lng   float64 `json:"lng"`
+	Type  string  `json:"type"`
+	Order int     `json:"order"` // posición en el recorrido; si todos son 0 se usa el orden del arreglo
+	// Radio de validación propio; 0 = usar el del evento
+	RadiusM float64 `json:"radius_m,omitempty"`
+	// Distancia desde la salida a lo largo del trazado (calculada al guardar la ruta)
+	DistanceM *float64 `json:"distance_m,omitempty"`
+}
+
+// RouteMetrics son las métricas calculadas de una ruta y guardadas en events
+type RouteMetrics struct {
+	DistanceM      *float64 `db:"distance_m" json:"distance_m,omitempty"`
+	ElevationGainM *float64 `db:"elevation_gain_m" json:"elevation_gain_m,omitempty"`
+	ElevationLossM *float64 `db:"elevation_loss_m" json:"elevation_loss_m,omitempty"`
+}
+
+func (r Route) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+func (r *Route) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, r)
+	case string:
+		return json.Unmarshal([]byte(v), r)
+	}
+	return errors.New("route: tipo no soportado")
+}
+
+// Checkpoint busca un checkpoint por ID
+func (r Route) Checkpoint(id int) (Checkpoint, bool) {
+	for _, cp := range r.Checkpoints {
+		if cp.ID == id {
+			return cp, true
+		}
+	}
+	return Checkpoint{}, false
+}
+
+// SortCheckpoints devuelve los checkpoints en orden de recorrido (por Order, estable)
+func SortCheckpoints(cps []Checkpoint) []Checkpoint {
+	sorted := make([]Checkpoint, len(cps))
+	copy(sorted, cps)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Order < sorted[j].Order
+	})
+	return sorted
+}
+
+// FieldError es un error de validación asociado a un campo del request
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, fe := range v {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (v *ValidationErrors) add(field, format string, args ...interface{}) {
+	*v = append(*v, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate revisa coordenadas, IDs únicos de checkpoints y que haya exactamente
+// una salida y una meta (la salida primero y la meta al final del recorrido).
+func (r Route) Validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if len(r.Polyline) == 1 {
+		errs.add("route.polyline", "debe tener al menos 2 puntos")
+	}
+	for i, p := range r.Polyline {
+		validateCoords(&errs, fmt.Sprintf("route.polyline[%d]", i), p.Lat, p.Lng)
+	}
+
+	if len(r.Checkpoints) < 2 {
+		errs.add("route.checkpoints", "debe tener al menos un checkpoint 'start' y uno 'finish'")
+	}
+
+	ids := map[int]bool{}
+	orders := map[int]bool{}
+	starts, finishes := 0, 0
+	for i, cp := range r.Checkpoints {
+		field := fmt.Sprintf("route.checkpoints[%d]", i)
+		if cp.ID <= 0 {
+			errs.add(field+".id", "debe ser un entero positivo")
+		} else if ids[cp.ID] {
+			errs.add(field+".id", "id %d repetido", cp.ID)
+		}
+		ids[cp.ID] = true
+
+		if strings.TrimSpace(cp.Name) == "" {
+			errs.add(field+".name", "es obligatorio")
+		}
+		validateCoords(&errs, field, cp.Lat, cp.Lng)
+
+		switch cp.Type {
+		case CheckpointTypeStart:
+			starts++
+		case CheckpointTypeFinish:
+			finishes++
+		case CheckpointTypeIntermediate:
+		default:
+			errs.add(field+".type", "debe ser 'start', 'checkpoint' o 'finish'")
+		}
+
+		if cp.Order < 0 {
+			errs.add(field+".order", "no puede ser negativo")
+		} else if cp.Order > 0 {
+			if orders[cp.Order] {
+				errs.add(field+".order", "order %d repetido", cp.Order)
+			}
+			orders[cp.Order] = true
+		}
+		if cp.RadiusM < 0 {
+			errs.add(field+".radius_m", "no puede ser negativo")
+		}
+	}
+
+	if starts != 1 {
+		errs.add("route.checkpoints", "debe haber exactamente un checkpoint 'start' (hay %d)", starts)
+	}
+	if finishes != 1 {
+		errs.add("route.checkpoints", "debe haber exactamente un checkpoint 'finish' (hay %d)", finishes)
+	}
+	if starts == 1 && finishes == 1 {
+		course := SortCheckpoints(r.Checkpoints)
+		if course[0].Type != CheckpointTypeStart {
+			errs.add("route.checkpoints", "el checkpoint 'start' debe ser el primero del recorrido")
+		}
+		if course[len(course)-1].Type != CheckpointTypeFinish {
+			errs.add("route.checkpoints", "el checkpoint 'finish' debe ser el último del recorrido")
+		}
+	}
+
+	return errs
+}
+
+func validateCoords(errs *ValidationErrors, field string, lat, lng float64) {
+	if lat < -90 || lat > 90 {
+		errs.add(field+".lat", "debe estar entre -90 y 90")
+	}
+	if lng < -180 || lng > 180 {
+		errs.add(field+".lng", "debe estar entre -180 y 180")
+	}
+}
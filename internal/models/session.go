@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Motivos de revocación de una sesión
+const (
+	SessionRevokedLogout        = "logout"
+	SessionRevokedLogoutAll     = "logout_all"
+	SessionRevokedPasswordReset = "password_reset"
+	SessionRevokedTokenReuse    = "token_reuse"
+	SessionRevokedSuspended     = "suspended"
+)
+
+// Session es un login de un dispositivo
+type Session struct {
+	ID            int        `db:"id" json:"id"`
+	UserID        int        `db:"user_id" json:"user_id"`
+	TokenHash     string     `db:"refresh_token_hash" json:"-"`
+	PreviousHash  *string    `db:"previous_token_hash" json:"-"`
+	UserAgent     *string    `db:"user_agent" json:"user_agent,omitempty"`
+	IP            *string    `db:"ip" json:"ip,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	LastUsedAt    time.Time  `db:"last_used_at" json:"last_used_at"`
+	ExpiresAt     time.Time  `db:"expires_at" json:"expires_at"`
+	RevokedAt     *time.Time `db:"revoked_at" json:"revoked_at,omitempty"`
+	RevokedReason *string    `db:"revoked_reason" json:"revoked_reason,omitempty"`
+	Current       bool       `db:"-" json:"current"` // la sesión del token con el que se consulta
+}
@@ -12,6 +12,13 @@ type User struct {
 	Password  string    `db:"password" json:"-"` // nunca se envía en JSON
 	Role      string    `db:"role" json:"role"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	Birthdate *time.Time `db:"birthdate" json:"birthdate,omitempty"`
+	Gender    *string    `db:"gender" json:"gender,omitempty"` // female | male | other
+	Locale    string     `db:"locale" json:"locale"`           // idioma de los emails: es | en
+	PasswordChangedAt *time.Time `db:"password_changed_at" json:"-"` // solo informativo: al cambiarla se revocan las sesiones (revokeUserSessionsTx)
+	EmailVerifiedAt   *time.Time `db:"email_verified_at" json:"email_verified_at,omitempty"`
+	SuspendedAt       *time.Time `db:"suspended_at" json:"suspended_at,omitempty"`
+	SuspendedReason   *string    `db:"suspended_reason" json:"suspended_reason,omitempty"`
 }
 
 type Registration struct {
@@ -19,6 +26,8 @@ type Registration struct {
 	UserID  int       `db:"user_id" json:"user_id"`
 	EventID int       `db:"event_id" json:"event_id"`
 	Date    time.Time `db:"date" json:"date"`
+	BibNumber *int    `db:"bib_number" json:"bib_number,omitempty"`
+	CategoryID *int   `db:"category_id" json:"category_id,omitempty"`
 
 	User User `json:"user"` // opcional para devolver info del usuario
 }
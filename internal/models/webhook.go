@@ -0,0 +1,116 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Tipos de evento a los que se puede suscribir un webhook
+const (
+	WebhookRegistrationCreated   = "registration.created"
+	WebhookRegistrationCancelled = "registration.cancelled"
+	WebhookEventUpdated          = "event.updated"
+	WebhookEventStatusChanged    = "event.status_changed"
+	WebhookEventCancelled        = "event.cancelled"
+	WebhookEventPostponed        = "event.postponed"
+	WebhookCheckinRecorded       = "checkin.recorded"
+)
+
+var WebhookEventTypes = []string{
+	WebhookRegistrationCreated,
+	WebhookRegistrationCancelled,
+	WebhookEventUpdated,
+	WebhookEventStatusChanged,
+	WebhookEventCancelled,
+	WebhookEventPostponed,
+	WebhookCheckinRecorded,
+}
+
+func ValidWebhookEventType(t string) bool {
+	for _, et := range WebhookEventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Estados de una entrega
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySending   = "sending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+type WebhookEndpoint struct {
+	ID         int            `db:"id" json:"id"`
+	OwnerID    int            `db:"owner_id" json:"owner_id"`
+	EventID    *int           `db:"event_id" json:"event_id,omitempty"` // nil = todos los eventos del organizer
+	URL        string         `db:"url" json:"url"`
+	Secret     string         `db:"secret" json:"-"` // solo se muestra al crearlo
+	EventTypes pq.StringArray `db:"event_types" json:"event_types"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int             `db:"id" json:"id"`
+	EndpointID     int             `db:"endpoint_id" json:"endpoint_id"`
+	EventID        *int            `db:"event_id" json:"event_id,omitempty"`
+	EventType      string          `db:"event_type" json:"event_type"`
+	Payload        json.RawMessage `db:"payload" json:"payload"`
+	Status         string          `db:"status" json:"status"`
+	Attempts       int             `db:"attempts" json:"attempts"`
+	MaxAttempts    int             `db:"max_attempts" json:"max_attempts"`
+	NextAttemptAt  time.Time       `db:"next_attempt_at" json:"next_attempt_at"`
+	ResponseStatus *int            `db:"response_status" json:"response_status,omitempty"`
+	LastError      *string         `db:"last_error" json:"last_error,omitempty"`
+	ReplayOf       *int            `db:"replay_of" json:"replay_of,omitempty"`
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
+	DeliveredAt    *time.Time      `db:"delivered_at" json:"delivered_at,omitempty"`
+}
+
+// WebhookDeliveryTarget es una entrega lista para enviar (con la URL y el secreto)
+type WebhookDeliveryTarget struct {
+	WebhookDelivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+// WebhookEnvelope es el cuerpo JSON que recibe el endpoint
+type WebhookEnvelope struct {
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	EventID    int         `json:"event_id"`
+	Data       interface{} `json:"data"`
+}
+
+// WebhookRegistration es el "data" de registration.created / registration.cancelled
+type WebhookRegistration struct {
+	RegistrationID int     `db:"registration_id" json:"registration_id"`
+	UserID         int     `db:"user_id" json:"user_id"`
+	UserName       string  `db:"user_name" json:"user_name"`
+	BibNumber      *int    `db:"bib_number" json:"bib_number,omitempty"`
+	CategoryID     *int    `db:"category_id" json:"category_id,omitempty"`
+	CategoryName   *string `db:"category_name" json:"category_name,omitempty"`
+}
+
+// WebhookCheckin es el "data" de checkin.recorded
+type WebhookCheckin struct {
+	CheckinID    int       `json:"checkin_id"`
+	UserID       int       `json:"user_id"`
+	CheckpointID int       `json:"checkpoint_id"`
+	Lat          float64   `json:"lat"`
+	Lng          float64   `json:"lng"`
+	RecordedBy   *int      `json:"recorded_by,omitempty"`
+	RecordedAt   time.Time `json:"recorded_at"`
+}
+
+// WebhookStatusChange es el "data" de event.status_changed, event.cancelled y event.postponed
+type WebhookStatusChange struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Reason *string `json:"reason,omitempty"`
+}
@@ -0,0 +1,23 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+
+	"sport-events-backend/internal/mailer"
+	"sport-events-backend/internal/models"
+)
+
+// EmailChannel renderiza la plantilla con el nombre del tipo de notificación
+// (p.ej. event_cancelled) en el idioma del destinatario
+type EmailChannel struct {
+	Mailer *mailer.Mailer
+}
+
+func (c EmailChannel) Send(ctx context.Context, n models.Notification) error {
+	var data map[string]interface{}
+	if err := json.Unmarshal(n.Payload, &data); err != nil {
+		return err
+	}
+	return c.Mailer.SendTemplate(ctx, n.Recipient, n.Kind, n.Locale, data)
+}
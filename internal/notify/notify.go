@@ -0,0 +1,44 @@
+// Package notify entrega las notificaciones del outbox por distintos canales.
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"sport-events-backend/internal/mailer"
+	"sport-events-backend/internal/models"
+)
+
+// Channel entrega una notificación; un error provoca un reintento
+type Channel interface {
+	Send(ctx context.Context, n models.Notification) error
+}
+
+// Dispatcher elige el canal según notification.channel
+type Dispatcher struct {
+	channels map[string]Channel
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{channels: map[string]Channel{}}
+}
+
+func (d *Dispatcher) Register(name string, ch Channel) {
+	d.channels[name] = ch
+}
+
+func (d *Dispatcher) Deliver(ctx context.Context, n models.Notification) error {
+	ch, ok := d.channels[n.Channel]
+	if !ok {
+		return fmt.Errorf("canal %q no configurado", n.Channel)
+	}
+	return ch.Send(ctx, n)
+}
+
+// DefaultDispatcher configura los canales a partir de variables de entorno
+func DefaultDispatcher() *Dispatcher {
+	d := NewDispatcher()
+	d.Register(models.NotificationChannelEmail, EmailChannel{Mailer: mailer.Default()})
+	d.Register(models.NotificationChannelWebhook, NewWebhookChannel())
+	return d
+}
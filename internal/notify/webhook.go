@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sport-events-backend/internal/models"
+)
+
+// WebhookChannel hace POST del payload JSON a la URL del destinatario
+type WebhookChannel struct {
+	Client *http.Client
+}
+
+func NewWebhookChannel() WebhookChannel {
+	return WebhookChannel{Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (c WebhookChannel) Send(ctx context.Context, n models.Notification) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.Recipient, bytes.NewReader(n.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Notification-Id", strconv.Itoa(n.ID))
+	req.Header.Set("X-Notification-Kind", n.Kind)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook respondió %d", resp.StatusCode)
+	}
+	return nil
+}
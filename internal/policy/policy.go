@@ -0,0 +1,182 @@
+// Package policy define los permisos de la aplicación y quién los tiene.
+// Es el único lugar donde se decide quién puede hacer qué: los roles dan
+// capacidades globales y, para las acciones sobre un evento, los permisos los dan
+// el rol en el equipo del evento, los permisos concedidos por evento y el staff asignado.
+package policy
+
+import (
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+)
+
+// Permission identifica una acción protegida
+type Permission string
+
+// Permisos globales
+const (
+	EventCreate          Permission = "event:create"
+	EventRegister        Permission = "event:register"         // inscribirse en eventos
+	RegistrationCancel   Permission = "registration:cancel"    // cancelar la propia inscripción
+	RegistrationsReadOwn Permission = "registrations:read_own" // ver las propias inscripciones
+	CheckinSelf          Permission = "checkin:self"           // checkin GPS del propio corredor
+	OrganizerApply       Permission = "organizer:apply"
+	WebhooksManage       Permission = "webhooks:manage"
+	UsersManage          Permission = "users:manage"
+	ApplicationsReview   Permission = "applications:review"
+)
+
+// Permisos sobre un evento
+const (
+	EventView           Permission = "event:view" // ver el evento aunque no sea público
+	EventEdit           Permission = "event:edit" // datos, ruta, categorías y recordatorios
+	EventDelete         Permission = "event:delete"
+	EventStatus         Permission = "event:status" // transiciones de estado y cancelación
+	RegistrationsRead   Permission = "registrations:read"
+	RegistrationsManage Permission = "registrations:manage" // asignar dorsales
+	NotificationsRead   Permission = "notifications:read"
+	BibsRead            Permission = "bibs:read"
+	CheckinRecord       Permission = "checkin:record" // checkin en nombre de un corredor
+	StaffManage         Permission = "staff:manage"
+	MembersManage       Permission = "members:manage" // invitar y quitar miembros del equipo
+	GrantsManage        Permission = "grants:manage"
+)
+
+// eventPermissions: permisos cuyo alcance es un evento
+var eventPermissions = []Permission{
+	EventView, EventEdit, EventDelete, EventStatus,
+	RegistrationsRead, RegistrationsManage, NotificationsRead,
+	BibsRead, CheckinRecord, StaffManage, MembersManage, GrantsManage,
+}
+
+// rolePermissions: lo que puede hacer cada rol. Solo el admin tiene permisos de evento
+// por su rol (sobre todos los eventos); el resto los obtiene por evento.
+var rolePermissions = map[string][]Permission{
+	models.RoleRunner: {
+		EventRegister, RegistrationCancel, RegistrationsReadOwn, CheckinSelf, OrganizerApply,
+	},
+	models.RoleOrganizer: {EventCreate, WebhooksManage},
+	models.RoleAdmin: append([]Permission{
+		EventCreate, WebhooksManage, UsersManage, ApplicationsReview,
+	}, eventPermissions...),
+}
+
+// memberPermissions: lo que da cada rol del equipo del evento
+var memberPermissions = map[string][]Permission{
+	models.MemberOwner: eventPermissions,
+	models.MemberCoOrganizer: {
+		EventView, EventEdit, EventStatus, RegistrationsRead, RegistrationsManage,
+		NotificationsRead, BibsRead, CheckinRecord, StaffManage,
+	},
+	models.MemberViewer: {EventView, RegistrationsRead, NotificationsRead, BibsRead},
+	// El miembro staff recibe una asignación a todo el evento en event_staff; de ahí salen
+	// BibsRead y CheckinRecord, así que el organizador la puede limitar a checkpoints.
+	models.MemberStaff: {EventView},
+}
+
+// staffPermissions: lo que obtiene el staff asignado a checkpoints (event_staff).
+// CheckinRecord se limita además a los checkpoints asignados.
+var staffPermissions = []Permission{BibsRead, CheckinRecord}
+
+// unverifiedPermissions: no exigen el email verificado
+// (acciones sobre inscripciones ya hechas y las del staff del día de la carrera)
+var unverifiedPermissions = []Permission{
+	RegistrationCancel, RegistrationsReadOwn, CheckinSelf, BibsRead, CheckinRecord,
+}
+
+// Subject es el usuario que intenta la acción
+type Subject struct {
+	UserID int
+	Role   string
+}
+
+// Allows indica si el rol tiene el permiso
+func Allows(role string, p Permission) bool {
+	return contains(rolePermissions[role], p)
+}
+
+// IsEventPermission indica si el alcance del permiso es un evento
+func IsEventPermission(p Permission) bool {
+	return contains(eventPermissions, p)
+}
+
+// Grantable indica si el permiso se puede conceder a otro usuario sobre un evento.
+// Borrar el evento y gestionar el equipo y los permisos quedan reservados al owner.
+func Grantable(p Permission) bool {
+	return IsEventPermission(p) && p != EventDelete && p != MembersManage && p != GrantsManage
+}
+
+// MemberAllows indica si el rol del equipo da el permiso
+func MemberAllows(memberRole string, p Permission) bool {
+	return contains(memberPermissions[memberRole], p)
+}
+
+// RequiresVerifiedEmail indica si el permiso exige haber confirmado el email
+func RequiresVerifiedEmail(p Permission) bool {
+	return !contains(unverifiedPermissions, p)
+}
+
+// Decide aplica las reglas sobre un evento a partir de la relación ya cargada.
+// checkpointID limita CheckinRecord del staff a sus checkpoints (nil = cualquier checkpoint
+// del evento, solo staff sin checkpoint asignado).
+func Decide(s Subject, access models.EventAccess, p Permission, checkpointID *int) bool {
+	if !access.Exists {
+		return false
+	}
+	if s.Role == models.RoleAdmin && Allows(s.Role, p) {
+		return true
+	}
+	if MemberAllows(access.MemberRole, p) {
+		return true
+	}
+	for _, g := range access.Grants {
+		if Permission(g) == p {
+			return true
+		}
+	}
+	if !contains(staffPermissions, p) {
+		return false
+	}
+	if p != CheckinRecord {
+		return access.IsStaff()
+	}
+	if access.StaffEventWide {
+		return true
+	}
+	if checkpointID == nil {
+		return false
+	}
+	for _, cp := range access.StaffCheckpoints {
+		if int(cp) == *checkpointID {
+			return true
+		}
+	}
+	return false
+}
+
+// CanOnEvent indica si el usuario tiene el permiso sobre el evento.
+// Un evento inexistente se trata como sin permiso.
+func CanOnEvent(s Subject, eventID int, p Permission) (bool, error) {
+	return can(s, eventID, p, nil)
+}
+
+// CanAtCheckpoint es CanOnEvent para acciones sobre un checkpoint concreto
+func CanAtCheckpoint(s Subject, eventID, checkpointID int, p Permission) (bool, error) {
+	return can(s, eventID, p, &checkpointID)
+}
+
+func can(s Subject, eventID int, p Permission, checkpointID *int) (bool, error) {
+	access, err := repository.GetEventAccess(eventID, s.UserID)
+	if err != nil {
+		return false, err
+	}
+	return Decide(s, access, p, checkpointID), nil
+}
+
+func contains(perms []Permission, p Permission) bool {
+	for _, x := range perms {
+		if x == p {
+			return true
+		}
+	}
+	return false
+}
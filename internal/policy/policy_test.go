@@ -0,0 +1,195 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"sport-events-backend/internal/models"
+)
+
+func TestDecide(t *testing.T) {
+	cp := func(id int) *int { return &id }
+	coOrganizer := []Permission{
+		EventView, EventEdit, EventStatus, RegistrationsRead, RegistrationsManage,
+		NotificationsRead, BibsRead, CheckinRecord, StaffManage,
+	}
+	viewer := []Permission{EventView, RegistrationsRead, NotificationsRead, BibsRead}
+
+	tests := []struct {
+		name       string
+		subject    Subject
+		access     models.EventAccess
+		checkpoint *int
+		want       []Permission
+	}{
+		{
+			name:    "evento inexistente, ni el admin",
+			subject: Subject{UserID: 1, Role: models.RoleAdmin},
+			access:  models.EventAccess{Exists: false, MemberRole: models.MemberOwner},
+		},
+		{
+			name:    "admin sin relación con el evento",
+			subject: Subject{UserID: 1, Role: models.RoleAdmin},
+			access:  models.EventAccess{Exists: true},
+			want:    eventPermissions,
+		},
+		{
+			name:    "owner",
+			subject: Subject{UserID: 2, Role: models.RoleOrganizer},
+			access:  models.EventAccess{Exists: true, MemberRole: models.MemberOwner},
+			want:    eventPermissions,
+		},
+		{
+			name:    "co_organizer",
+			subject: Subject{UserID: 3, Role: models.RoleOrganizer},
+			access:  models.EventAccess{Exists: true, MemberRole: models.MemberCoOrganizer},
+			want:    coOrganizer,
+		},
+		{
+			name:    "viewer",
+			subject: Subject{UserID: 4, Role: models.RoleRunner},
+			access:  models.EventAccess{Exists: true, MemberRole: models.MemberViewer},
+			want:    viewer,
+		},
+		{
+			name:    "miembro staff sin asignación en event_staff",
+			subject: Subject{UserID: 5, Role: models.RoleRunner},
+			access:  models.EventAccess{Exists: true, MemberRole: models.MemberStaff},
+			want:    []Permission{EventView},
+		},
+		{
+			name:    "miembro staff con su asignación a todo el evento",
+			subject: Subject{UserID: 5, Role: models.RoleRunner},
+			access:  models.EventAccess{Exists: true, MemberRole: models.MemberStaff, StaffEventWide: true},
+			want:    []Permission{EventView, BibsRead, CheckinRecord},
+		},
+		{
+			name:    "organizer sin relación con el evento",
+			subject: Subject{UserID: 6, Role: models.RoleOrganizer},
+			access:  models.EventAccess{Exists: true},
+		},
+		{
+			name:    "runner sin relación con el evento",
+			subject: Subject{UserID: 7, Role: models.RoleRunner},
+			access:  models.EventAccess{Exists: true},
+		},
+		{
+			name:    "permisos concedidos",
+			subject: Subject{UserID: 8, Role: models.RoleRunner},
+			access: models.EventAccess{Exists: true, Grants: pq.StringArray{
+				string(RegistrationsRead), string(NotificationsRead),
+			}},
+			want: []Permission{RegistrationsRead, NotificationsRead},
+		},
+		{
+			name:    "permiso concedido además del rol de viewer",
+			subject: Subject{UserID: 8, Role: models.RoleRunner},
+			access: models.EventAccess{Exists: true, MemberRole: models.MemberViewer,
+				Grants: pq.StringArray{string(EventEdit)}},
+			want: append([]Permission{EventEdit}, viewer...),
+		},
+		{
+			name:    "staff de todo el evento",
+			subject: Subject{UserID: 9, Role: models.RoleRunner},
+			access:  models.EventAccess{Exists: true, StaffEventWide: true},
+			want:    []Permission{BibsRead, CheckinRecord},
+		},
+		{
+			name:       "staff de todo el evento en un checkpoint concreto",
+			subject:    Subject{UserID: 9, Role: models.RoleRunner},
+			access:     models.EventAccess{Exists: true, StaffEventWide: true},
+			checkpoint: cp(3),
+			want:       []Permission{BibsRead, CheckinRecord},
+		},
+		{
+			name:    "staff de checkpoint sin indicar checkpoint",
+			subject: Subject{UserID: 10, Role: models.RoleRunner},
+			access:  models.EventAccess{Exists: true, StaffCheckpoints: pq.Int64Array{3, 5}},
+			want:    []Permission{BibsRead},
+		},
+		{
+			name:       "staff de checkpoint en su checkpoint",
+			subject:    Subject{UserID: 10, Role: models.RoleRunner},
+			access:     models.EventAccess{Exists: true, StaffCheckpoints: pq.Int64Array{3, 5}},
+			checkpoint: cp(5),
+			want:       []Permission{BibsRead, CheckinRecord},
+		},
+		{
+			name:       "staff de checkpoint en otro checkpoint",
+			subject:    Subject{UserID: 10, Role: models.RoleRunner},
+			access:     models.EventAccess{Exists: true, StaffCheckpoints: pq.Int64Array{3, 5}},
+			checkpoint: cp(4),
+			want:       []Permission{BibsRead},
+		},
+		{
+			name:       "viewer con staff de checkpoint en otro checkpoint",
+			subject:    Subject{UserID: 11, Role: models.RoleRunner},
+			access:     models.EventAccess{Exists: true, MemberRole: models.MemberViewer, StaffCheckpoints: pq.Int64Array{3}},
+			checkpoint: cp(4),
+			want:       viewer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, p := range eventPermissions {
+				want := contains(tt.want, p)
+				if got := Decide(tt.subject, tt.access, p, tt.checkpoint); got != want {
+					t.Errorf("Decide(%s) = %v, se esperaba %v", p, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGrantable(t *testing.T) {
+	tests := []struct {
+		perm Permission
+		want bool
+	}{
+		{EventView, true},
+		{EventEdit, true},
+		{EventStatus, true},
+		{RegistrationsRead, true},
+		{RegistrationsManage, true},
+		{NotificationsRead, true},
+		{BibsRead, true},
+		{CheckinRecord, true},
+		{StaffManage, true},
+		{EventDelete, false},
+		{MembersManage, false},
+		{GrantsManage, false},
+		{EventCreate, false},
+		{UsersManage, false},
+		{Permission("event:unknown"), false},
+	}
+	for _, tt := range tests {
+		if got := Grantable(tt.perm); got != tt.want {
+			t.Errorf("Grantable(%s) = %v, se esperaba %v", tt.perm, got, tt.want)
+		}
+	}
+}
+
+func TestRequiresVerifiedEmail(t *testing.T) {
+	tests := []struct {
+		perm Permission
+		want bool
+	}{
+		{EventCreate, true},
+		{EventRegister, true},
+		{OrganizerApply, true},
+		{WebhooksManage, true},
+		{EventEdit, true},
+		{MembersManage, true},
+		{RegistrationCancel, false},
+		{RegistrationsReadOwn, false},
+		{CheckinSelf, false},
+		{BibsRead, false},
+		{CheckinRecord, false},
+	}
+	for _, tt := range tests {
+		if got := RequiresVerifiedEmail(tt.perm); got != tt.want {
+			t.Errorf("RequiresVerifiedEmail(%s) = %v, se esperaba %v", tt.perm, got, tt.want)
+		}
+	}
+}
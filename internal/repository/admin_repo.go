@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
+)
+
+var (
+	ErrUserNotFound  = errors.New("usuario no encontrado")
+	ErrUserSuspended = errors.New("la cuenta está suspendida")
+)
+
+// userAdminColumns: columnas de users que ve el panel de administración (sin la contraseña)
+const userAdminColumns = `id, name, email, role, created_at, birthdate, gender, locale, email_verified_at, suspended_at, suspended_reason`
+
+// SearchUsers lista usuarios con filtros y devuelve también el total sin paginar
+func SearchUsers(f models.UserFilter) ([]models.User, int, error) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	i := 1
+
+	if f.Query != "" {
+		where += fmt.Sprintf(" AND (name ILIKE $%d OR email ILIKE $%d)", i, i)
+		args = append(args, "%"+f.Query+"%")
+		i++
+	}
+	if f.Role != "" {
+		where += fmt.Sprintf(" AND role = $%d", i)
+		args = append(args, f.Role)
+		i++
+	}
+	if f.Suspended != nil {
+		if *f.Suspended {
+			where += " AND suspended_at IS NOT NULL"
+		} else {
+			where += " AND suspended_at IS NULL"
+		}
+	}
+
+	var total int
+	if err := config.DB.Get(&total, `SELECT COUNT(*) FROM users`+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	users := []models.User{}
+	query := `SELECT ` + userAdminColumns + ` FROM users` + where +
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", i, i+1)
+	args = append(args, f.Limit, f.Offset)
+	err := config.DB.Select(&users, query, args...)
+	return users, total, err
+}
+
+// SetUserRole cambia el rol del usuario. El cambio aplica en la siguiente request, porque
+// AuthMiddleware lee el rol vigente de la base.
+func SetUserRole(userID int, role string) error {
+	res, err := config.DB.Exec(`UPDATE users SET role = $1 WHERE id = $2`, role, userID)
+	if err != nil {
+		return err
+	}
+	return expectOneRow(res, ErrUserNotFound)
+}
+
+// SuspendUser suspende la cuenta y cierra todas sus sesiones
+func SuspendUser(userID int, reason string) error {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	const q = `UPDATE users SET suspended_at = COALESCE(suspended_at, NOW()), suspended_reason = $1 WHERE id = $2`
+	res, err := tx.Exec(q, nullIfEmpty(reason), userID)
+	if err != nil {
+		return err
+	}
+	if err := expectOneRow(res, ErrUserNotFound); err != nil {
+		return err
+	}
+	if _, err := revokeUserSessionsTx(tx, userID, models.SessionRevokedSuspended); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func ReactivateUser(userID int) error {
+	res, err := config.DB.Exec(`UPDATE users SET suspended_at = NULL, suspended_reason = NULL WHERE id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	return expectOneRow(res, ErrUserNotFound)
+}
+
+// PromoteToAdmin da el rol admin al usuario con ese email (arranque del primer admin)
+func PromoteToAdmin(email string) (bool, error) {
+	res, err := config.DB.Exec(`UPDATE users SET role = $1 WHERE email = $2 AND role <> $1`, models.RoleAdmin, email)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func expectOneRow(res sql.Result, notFound error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
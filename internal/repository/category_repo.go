@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
+)
+
+var (
+	ErrCategoryNotFound         = errors.New("categoría no encontrada en este evento")
+	ErrCategoryRequired         = errors.New("el evento tiene categorías: debes elegir una")
+	ErrCategoryHasRegistrations = errors.New("la categoría tiene inscripciones")
+)
+
+const categoryColumns = `
+	c.id, c.event_id, c.name, c.distance_m, c.route, c.capacity, c.start_time, c.price,
+	c.bib_range_start, c.bib_range_end, c.created_at,
+	(SELECT COUNT(*) FROM registrations r WHERE r.category_id = c.id) AS registered
+`
+
+// CreateCategory inserta una categoría del evento y devuelve su id
+func CreateCategory(c models.EventCategory) (int, error) {
+	const q = `
+		INSERT INTO event_categories (event_id, name, distance_m, route, capacity, start_time, price, bib_range_start, bib_range_end)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+	var id int
+	err := config.DB.Get(&id, q, c.EventID, c.Name, c.DistanceM, c.Route, c.Capacity, c.StartTime, c.Price, c.BibRangeStart, c.BibRangeEnd)
+	return id, err
+}
+
+// GetEventCategories lista las categorías del evento (por distancia, luego nombre)
+func GetEventCategories(eventID int) ([]models.EventCategory, error) {
+	cats := []models.EventCategory{}
+	q := `SELECT ` + categoryColumns + `
+		FROM event_categories c
+		WHERE c.event_id = $1
+		ORDER BY c.distance_m ASC NULLS LAST, c.name ASC`
+	err := config.DB.Select(&cats, q, eventID)
+	return cats, err
+}
+
+// GetCategory devuelve la categoría solo si pertenece al evento
+func GetCategory(eventID, categoryID int) (models.EventCategory, error) {
+	var c models.EventCategory
+	q := `SELECT ` + categoryColumns + `
+		FROM event_categories c
+		WHERE c.event_id = $1 AND c.id = $2`
+	err := config.DB.Get(&c, q, eventID, categoryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, ErrCategoryNotFound
+	}
+	return c, err
+}
+
+// UpdateCategory reemplaza los datos de la categoría. Con la fila del evento bloqueada,
+// si quedan cupos se promueve la lista de espera en la misma transacción.
+func UpdateCategory(c models.EventCategory) error {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var one int
+	if err := tx.Get(&one, `SELECT 1 FROM events WHERE id = $1 FOR UPDATE`, c.EventID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEventNotFound
+		}
+		return err
+	}
+
+	const q = `
+		UPDATE event_categories
+		SET name = $1,
+		    distance_m = $2,
+		    route = $3,
+		    capacity = $4,
+		    start_time = $5,
+		    price = $6,
+		    bib_range_start = $7,
+		    bib_range_end = $8
+		WHERE id = $9 AND event_id = $10
+	`
+	res, err := tx.Exec(q, c.Name, c.DistanceM, c.Route, c.Capacity, c.StartTime, c.Price,
+		c.BibRangeStart, c.BibRangeEnd, c.ID, c.EventID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrCategoryNotFound
+	}
+
+	if _, err := promoteFromWaitlist(tx, c.EventID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteCategory elimina la categoría si no tiene inscripciones
+func DeleteCategory(eventID, categoryID int) error {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var one int
+	if err := tx.Get(&one, `SELECT 1 FROM events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEventNotFound
+		}
+		return err
+	}
+	var total int
+	if err := tx.Get(&total, `SELECT COUNT(*) FROM registrations WHERE category_id = $1`, categoryID); err != nil {
+		return err
+	}
+	if total > 0 {
+		return ErrCategoryHasRegistrations
+	}
+	res, err := tx.Exec(`DELETE FROM event_categories WHERE id = $1 AND event_id = $2`, categoryID, eventID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrCategoryNotFound
+	}
+	return tx.Commit()
+}
+
+// GetRunnerRoute devuelve la ruta que corre el usuario: la de su categoría si tiene,
+// si no la del evento (nil si no hay ruta)
+func GetRunnerRoute(eventID, userID int) (*models.Route, error) {
+	var route *models.Route
+	const q = `
+		SELECT COALESCE(c.route, e.route)
+		FROM events e
+		LEFT JOIN registrations r ON r.event_id = e.id AND r.user_id = $2
+		LEFT JOIN event_categories c ON c.id = r.category_id
+		WHERE e.id = $1
+	`
+	err := config.DB.Get(&route, q, eventID, userID)
+	return route, err
+}
+
+// GetRegistrationCategories devuelve la categoría de cada inscrito (user_id → category_id)
+func GetRegistrationCategories(eventID int) (map[int]int, error) {
+	var rows []struct {
+		UserID     int `db:"user_id"`
+		CategoryID int `db:"category_id"`
+	}
+	const q = `SELECT user_id, category_id FROM registrations WHERE event_id = $1 AND category_id IS NOT NULL`
+	if err := config.DB.Select(&rows, q, eventID); err != nil {
+		return nil, err
+	}
+	byUser := make(map[int]int, len(rows))
+	for _, row := range rows {
+		byUser[row.UserID] = row.CategoryID
+	}
+	return byUser, nil
+}
+
+// resolveCategoryTx valida la categoría elegida para inscribirse: obligatoria si el
+// evento tiene categorías y debe pertenecer al evento.
+func resolveCategoryTx(tx *sqlx.Tx, eventID int, categoryID *int) error {
+	var total int
+	if err := tx.Get(&total, `SELECT COUNT(*) FROM event_categories WHERE event_id = $1`, eventID); err != nil {
+		return err
+	}
+	if categoryID == nil {
+		if total > 0 {
+			return ErrCategoryRequired
+		}
+		return nil
+	}
+	var one int
+	if err := tx.Get(&one, `SELECT 1 FROM event_categories WHERE id = $1 AND event_id = $2`, *categoryID, eventID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCategoryNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// hasRoomTx indica si quedan cupos en el evento y, si aplica, en la categoría.
+// Debe llamarse con la fila del evento bloqueada.
+func hasRoomTx(tx *sqlx.Tx, eventID int, categoryID *int) (bool, error) {
+	var capacity sql.NullInt64
+	if err := tx.Get(&capacity, `SELECT capacity FROM events WHERE id = $1`, eventID); err != nil {
+		return false, err
+	}
+	if capacity.Valid {
+		var total int
+		if err := tx.Get(&total, `SELECT COUNT(*) FROM registrations WHERE event_id = $1`, eventID); err != nil {
+			return false, err
+		}
+		if int64(total) >= capacity.Int64 {
+			return false, nil
+		}
+	}
+	if categoryID == nil {
+		return true, nil
+	}
+
+	var catCapacity sql.NullInt64
+	if err := tx.Get(&catCapacity, `SELECT capacity FROM event_categories WHERE id = $1`, *categoryID); err != nil {
+		return false, err
+	}
+	if !catCapacity.Valid {
+		return true, nil
+	}
+	var total int
+	if err := tx.Get(&total, `SELECT COUNT(*) FROM registrations WHERE category_id = $1`, *categoryID); err != nil {
+		return false, err
+	}
+	return int64(total) < catCapacity.Int64, nil
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
 	"time"
+
+	"github.com/jmoiron/sqlx"
 )
 
 type Checkin struct {
@@ -12,13 +15,125 @@ type Checkin struct {
 	CheckpointID int      `db:"checkpoint_id" json:"checkpoint_id"`
 	Lat         float64   `db:"lat" json:"lat"`
 	Lng         float64   `db:"lng" json:"lng"`
+	AccuracyM   *float64  `db:"accuracy_m" json:"accuracy_m,omitempty"`
+	RecordedBy  *int      `db:"recorded_by" json:"recorded_by,omitempty"` // staff que lo registró
 	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 }
 
-func CreateCheckin(userID, eventID, checkpointID int, lat, lng float64) error {
+// CreateCheckin guarda el checkin y devuelve su ID.
+// recordedBy es el staff que lo registró en nombre del corredor (nil si lo hizo él mismo).
+// checkSequence recibe los checkpoints ya registrados por el corredor y decide si el nuevo
+// es válido; se ejecuta con un advisory lock por corredor y evento, así dos checkins
+// concurrentes no pueden validar contra el mismo recorrido. Su error se devuelve tal cual.
+// El webhook checkin.recorded se encola en la misma transacción.
+func CreateCheckin(userID, eventID, checkpointID int, lat, lng float64, accuracy *float64, recordedBy *int, checkSequence func(visited []int) error) (int, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('checkins'), hashtext($1 || ':' || $2))`, eventID, userID); err != nil {
+		return 0, err
+	}
+	visited, err := userCheckpointIDsTx(tx, userID, eventID)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkSequence(visited); err != nil {
+		return 0, err
+	}
+
 	query := `
-        INSERT INTO checkins (user_id, event_id, checkpoint_id, lat, lng)
-        VALUES ($1, $2, $3, $4, $5)`
-	_, err := config.DB.Exec(query, userID, eventID, checkpointID, lat, lng)
-	return err
+        INSERT INTO checkins (user_id, event_id, checkpoint_id, lat, lng, accuracy_m, recorded_by)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id, created_at`
+	var row struct {
+		ID        int       `db:"id"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+	if err := tx.Get(&row, query, userID, eventID, checkpointID, lat, lng, accuracy, recordedBy); err != nil {
+		return 0, err
+	}
+
+	data := models.WebhookCheckin{
+		CheckinID:    row.ID,
+		UserID:       userID,
+		CheckpointID: checkpointID,
+		Lat:          lat,
+		Lng:          lng,
+		RecordedBy:   recordedBy,
+		RecordedAt:   row.CreatedAt,
+	}
+	if err := enqueueWebhooksTx(tx, eventID, models.WebhookCheckinRecorded, data); err != nil {
+		return 0, err
+	}
+	return row.ID, tx.Commit()
+}
+
+// GetCheckinsByEventTx devuelve los checkins del evento en orden cronológico
+func GetCheckinsByEventTx(tx *sqlx.Tx, eventID int) ([]Checkin, error) {
+	var rows []Checkin
+	const q = `
+		SELECT id, user_id, event_id, checkpoint_id, lat, lng, accuracy_m, recorded_by, created_at
+		FROM checkins
+		WHERE event_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+	err := tx.Select(&rows, q, eventID)
+	return rows, err
+}
+
+// EventHasCheckins indica si el evento ya tiene algún checkin registrado
+func EventHasCheckins(eventID int) (bool, error) {
+	var ok bool
+	err := config.DB.Get(&ok, `SELECT EXISTS(SELECT 1 FROM checkins WHERE event_id = $1)`, eventID)
+	return ok, err
+}
+
+// userCheckpointIDsTx devuelve los checkpoints ya registrados por el usuario en el evento
+func userCheckpointIDsTx(tx *sqlx.Tx, userID, eventID int) ([]int, error) {
+	var ids []int
+	const q = `
+		SELECT checkpoint_id
+		FROM checkins
+		WHERE user_id = $1 AND event_id = $2
+		ORDER BY created_at ASC, id ASC
+	`
+	err := tx.Select(&ids, q, userID, eventID)
+	return ids, err
+}
+
+// liveCheckinsQuery numera la llegada a cada checkpoint antes de filtrar por ID.
+// Cada categoría puede tener su propia ruta, así que la posición se cuenta por categoría.
+const liveCheckinsQuery = `
+	SELECT * FROM (
+		SELECT
+			c.id,
+			c.event_id,
+			c.user_id,
+			u.name AS user_name,
+			reg.bib_number,
+			c.checkpoint_id,
+			c.created_at,
+			ROW_NUMBER() OVER (PARTITION BY reg.category_id, c.checkpoint_id ORDER BY c.created_at, c.id) AS position
+		FROM checkins c
+		JOIN users u ON u.id = c.user_id
+		LEFT JOIN registrations reg ON reg.event_id = c.event_id AND reg.user_id = c.user_id
+		WHERE c.event_id = $1
+	) t
+`
+
+// GetLiveCheckinsAfter devuelve los checkins del evento con ID mayor a afterID (replay del stream)
+func GetLiveCheckinsAfter(eventID, afterID int) ([]models.LiveCheckin, error) {
+	var rows []models.LiveCheckin
+	err := config.DB.Select(&rows, liveCheckinsQuery+` WHERE t.id > $2 ORDER BY t.id ASC`, eventID, afterID)
+	return rows, err
+}
+
+// GetLiveCheckin devuelve un checkin con su posición en el checkpoint
+func GetLiveCheckin(eventID, checkinID int) (models.LiveCheckin, error) {
+	var row models.LiveCheckin
+	err := config.DB.Get(&row, liveCheckinsQuery+` WHERE t.id = $2`, eventID, checkinID)
+	return row, err
 }
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"sport-events-backend/internal/config"
+)
+
+var ErrInvalidVerificationToken = errors.New("el enlace de verificación no es válido o expiró")
+
+func CreateEmailVerificationToken(userID int, tokenHash string, expiresAt time.Time) error {
+	const q = `INSERT INTO email_verification_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)`
+	_, err := config.DB.Exec(q, userID, tokenHash, expiresAt)
+	return err
+}
+
+// GetVerificationRequests devuelve cuántos enlaces se pidieron desde since y cuándo fue el último
+func GetVerificationRequests(userID int, since time.Time) (int, *time.Time, error) {
+	var row struct {
+		Total int        `db:"total"`
+		Last  *time.Time `db:"last"`
+	}
+	const q = `
+		SELECT COUNT(*) FILTER (WHERE created_at >= $2) AS total, MAX(created_at) AS last
+		FROM email_verification_tokens
+		WHERE user_id = $1
+	`
+	err := config.DB.Get(&row, q, userID, since)
+	return row.Total, row.Last, err
+}
+
+// VerifyEmailWithToken consume el token y marca el email como verificado
+func VerifyEmailWithToken(tokenHash string) (int, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var userID int
+	const lockQ = `
+		SELECT user_id FROM email_verification_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		FOR UPDATE
+	`
+	if err := tx.Get(&userID, lockQ, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrInvalidVerificationToken
+		}
+		return 0, err
+	}
+
+	const userQ = `UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW()) WHERE id = $1`
+	if _, err := tx.Exec(userQ, userID); err != nil {
+		return 0, err
+	}
+	const usedQ = `UPDATE email_verification_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`
+	if _, err := tx.Exec(usedQ, userID); err != nil {
+		return 0, err
+	}
+	return userID, tx.Commit()
+}
+
+// IsEmailVerified indica si el usuario ya confirmó su email
+func IsEmailVerified(userID int) (bool, error) {
+	var verified bool
+	err := config.DB.Get(&verified, `SELECT email_verified_at IS NOT NULL FROM users WHERE id = $1`, userID)
+	return verified, err
+}
+
+// PurgeEmailVerificationTokens borra los tokens usados o vencidos hace más de olderThan
+func PurgeEmailVerificationTokens(olderThan time.Duration) (int64, error) {
+	const q = `
+		DELETE FROM email_verification_tokens
+		WHERE COALESCE(used_at, expires_at) < NOW() - ($1 * INTERVAL '1 second')
+	`
+	res, err := config.DB.Exec(q, int64(olderThan.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
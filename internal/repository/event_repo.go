@@ -1,51 +1,136 @@
 package repository
 
 import (
-	"time"
+	"database/sql"
 	"errors"
 	"fmt"
-	"encoding/json" 
+	"time"
 	
 	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/lifecycle"
 	"sport-events-backend/internal/models"
 )
 
 // CreateEvent inserta un evento y devuelve su id.
+// El creador queda como owner del equipo del evento en la misma transacción.
 func CreateEvent(e models.Event) (int, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
 	var id int
 	query := `
-		INSERT INTO events (name, description, type, date, location, route, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO events (name, description, type, date, location, route, created_by, capacity, checkin_mode, checkpoint_radius_m,
+			distance_m, elevation_gain_m, elevation_loss_m, bib_range_start, bib_range_end, age_groups,
+			registration_opens_at, registration_closes_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 		RETURNING id
 	`
-	err := config.DB.QueryRow(query, e.Name, e.Description, e.Type, e.Date, e.Location, e.Route, e.CreatedBy).Scan(&id)
-	return id, err
+	err = tx.QueryRow(query, e.Name, e.Description, e.Type, e.Date, e.Location, e.Route, e.CreatedBy, e.Capacity, e.CheckinMode, e.CheckpointRadiusM,
+		e.DistanceM, e.ElevationGainM, e.ElevationLossM, e.BibRangeStart, e.BibRangeEnd, e.AgeGroups,
+		e.RegistrationOpensAt, e.RegistrationClosesAt).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	if err := addEventMemberTx(tx, id, e.CreatedBy, models.MemberOwner, &e.CreatedBy); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
 }
 
 // GetAllEvents obtiene todos los eventos como []models.Event
 func GetAllEvents() ([]models.Event, error) {
 	var events []models.Event
 	query := `
-		SELECT id, name, description, type, date, location, route, created_by, created_at, status
+		SELECT id, name, description, type, date, location, route, created_by, created_at, status, capacity, checkin_mode, checkpoint_radius_m,
+			distance_m, elevation_gain_m, elevation_loss_m, bib_range_start, bib_range_end, age_groups,
+			registration_opens_at, registration_closes_at, results_finalized_at
 		FROM events
-		WHERE status <> 'cancelled'
+		WHERE status NOT IN ('cancelled', 'draft')
 		ORDER BY date ASC
 	`
 	err := config.DB.Select(&events, query)
 	return events, err
 }
 
-// RegisterUserToEvent registra a un usuario en un evento.
-func RegisterUserToEvent(userID, eventID int) error {
-	query := `INSERT INTO registrations (user_id, event_id, date) VALUES ($1, $2, $3)`
-	_, err := config.DB.Exec(query, userID, eventID, time.Now())
-	return err
+// RegisterUserToEvent registra a un usuario en un evento (y en la categoría elegida, si el
+// evento tiene categorías). Si el evento o la categoría alcanzaron su capacidad, el usuario
+// queda en lista de espera.
+// La fila del evento se bloquea (FOR UPDATE) para que el conteo de cupos sea consistente.
+func RegisterUserToEvent(userID, eventID int, categoryID *int) (models.RegistrationResult, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return models.RegistrationResult{}, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	if err := tx.Get(&status, `SELECT status FROM events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.RegistrationResult{}, ErrEventNotFound
+		}
+		return models.RegistrationResult{}, err
+	}
+	if err := lifecycle.CheckRegistration(status); err != nil {
+		return models.RegistrationResult{}, err
+	}
+	if err := resolveCategoryTx(tx, eventID, categoryID); err != nil {
+		return models.RegistrationResult{}, err
+	}
+
+	var registered, waitlisted bool
+	if err := tx.Get(&registered, `SELECT EXISTS(SELECT 1 FROM registrations WHERE user_id = $1 AND event_id = $2)`, userID, eventID); err != nil {
+		return models.RegistrationResult{}, err
+	}
+	if registered {
+		return models.RegistrationResult{}, ErrAlreadyRegistered
+	}
+	if err := tx.Get(&waitlisted, `SELECT EXISTS(SELECT 1 FROM waitlist WHERE user_id = $1 AND event_id = $2)`, userID, eventID); err != nil {
+		return models.RegistrationResult{}, err
+	}
+	if waitlisted {
+		return models.RegistrationResult{}, ErrAlreadyWaitlisted
+	}
+
+	room, err := hasRoomTx(tx, eventID, categoryID)
+	if err != nil {
+		return models.RegistrationResult{}, err
+	}
+
+	if room {
+		bib, err := insertRegistrationTx(tx, userID, eventID, categoryID)
+		if err != nil {
+			return models.RegistrationResult{}, err
+		}
+		if err := tx.Commit(); err != nil {
+			return models.RegistrationResult{}, err
+		}
+		return models.RegistrationResult{Status: models.RegistrationStatusRegistered, BibNumber: bib, CategoryID: categoryID}, nil
+	}
+
+	// Evento o categoría llenos → lista de espera (la posición se cuenta dentro de la categoría)
+	var waitID int
+	const insertQ = `INSERT INTO waitlist (user_id, event_id, category_id) VALUES ($1, $2, $3) RETURNING id`
+	if err := tx.Get(&waitID, insertQ, userID, eventID, categoryID); err != nil {
+		return models.RegistrationResult{}, err
+	}
+	var position int
+	const posQ = `SELECT COUNT(*) FROM waitlist WHERE event_id = $1 AND category_id IS NOT DISTINCT FROM $2 AND id <= $3`
+	if err := tx.Get(&position, posQ, eventID, categoryID, waitID); err != nil {
+		return models.RegistrationResult{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return models.RegistrationResult{}, err
+	}
+	return models.RegistrationResult{Status: models.RegistrationStatusWaitlisted, Position: position, CategoryID: categoryID}, nil
 }
 // GetRegistrationsByEvent obtiene todas las inscripciones para un evento específico.
 func GetRegistrationsByEvent(eventID int) ([]models.Registration, error) {
 	var regs []models.Registration
 	// Primero obtenemos las inscripciones básicas
-	query := `SELECT id, user_id, event_id, date FROM registrations WHERE event_id = $1`
+	query := `SELECT id, user_id, event_id, date, bib_number, category_id FROM registrations WHERE event_id = $1`
 	if err := config.DB.Select(&regs, query, eventID); err != nil {
 		return nil, err
 	}
@@ -65,7 +150,7 @@ func GetRegistrationsByEvent(eventID int) ([]models.Registration, error) {
 func GetEventsByCreator(userID int) ([]models.EventSummary, error) {
 	var evts []models.EventSummary
 	query := `
-		SELECT id, name, type, date, location, created_by
+		SELECT id, name, type, date, location, created_by, status
 		FROM events
 		WHERE created_by = $1
 		ORDER BY date DESC;
@@ -83,9 +168,13 @@ func GetUserRegistrationsWithEvents(userID int) ([]models.RegistrationWithEvent,
 			e.type       AS type,
 			e.date       AS date,
 			e.location   AS location,
-			r.date       AS registered_at
+			r.date       AS registered_at,
+			r.bib_number AS bib_number,
+			c.id         AS category_id,
+			c.name       AS category_name
 		FROM registrations r
 		JOIN events e ON e.id = r.event_id
+		LEFT JOIN event_categories c ON c.id = r.category_id
 		WHERE r.user_id = $1
 		ORDER BY e.date DESC;
 	`
@@ -126,7 +215,20 @@ func GetEventByID(id int) (models.Event, error) {
 		created_at,
 		status,
 		cancelled_at,
-		cancellation_reason
+		cancellation_reason,
+		status_changed_at,
+		capacity,
+		checkin_mode,
+		checkpoint_radius_m,
+		distance_m,
+		elevation_gain_m,
+		elevation_loss_m,
+		bib_range_start,
+		bib_range_end,
+		age_groups,
+		registration_opens_at,
+		registration_closes_at,
+		results_finalized_at
 		FROM events
 		WHERE id = $1
 	`
@@ -134,8 +236,36 @@ func GetEventByID(id int) (models.Event, error) {
 	return e, err
 }
 
-// Actualizar solo si el owner coincide (WHERE id=? AND created_by=?)
-func UpdateEventByOwner(e models.Event, ownerID int) (bool, error) {
+// UpdateEvent actualiza los datos del evento; el permiso (policy.EventEdit) lo valida el llamador.
+// Si cambian la fecha o el lugar, se encola el aviso a los inscritos en la misma transacción
+// (y con fecha nueva se reprograman los recordatorios ya enviados).
+// Si quedan cupos, la lista de espera se promueve en la misma transacción.
+func UpdateEvent(e models.Event) (bool, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// La comparación de fechas se hace en SQL, con la misma conversión que usa el UPDATE
+	var before struct {
+		Date        time.Time `db:"date"`
+		Location    string    `db:"location"`
+		DateChanged bool      `db:"date_changed"`
+	}
+	const lockQ = `
+		SELECT date, location, date IS DISTINCT FROM $2::timestamp AS date_changed
+		FROM events
+		WHERE id = $1
+		FOR UPDATE
+	`
+	if err := tx.Get(&before, lockQ, e.ID, e.Date); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
 	const q = `
 		UPDATE events
 		SET name = $1,
@@ -143,43 +273,106 @@ func UpdateEventByOwner(e models.Event, ownerID int) (bool, error) {
 		    type = $3,
 		    date = $4,
 		    location = $5,
-		    route = $6
-		WHERE id = $7 AND created_by = $8
+		    route = $6,
+		    capacity = $7,
+		    checkin_mode = $8,
+		    checkpoint_radius_m = $9,
+		    distance_m = $10,
+		    elevation_gain_m = $11,
+		    elevation_loss_m = $12,
+		    bib_range_start = $13,
+		    bib_range_end = $14,
+		    age_groups = $15,
+		    registration_opens_at = $16,
+		    registration_closes_at = $17
+		WHERE id = $18
 		RETURNING id
 	`
 	var id int
-	if err := config.DB.Get(&id, q,
-		e.Name, e.Description, e.Type, e.Date, e.Location, e.Route,
-		e.ID, ownerID,
+	if err := tx.Get(&id, q,
+		e.Name, e.Description, e.Type, e.Date, e.Location, e.Route, e.Capacity, e.CheckinMode, e.CheckpointRadiusM,
+		e.DistanceM, e.ElevationGainM, e.ElevationLossM, e.BibRangeStart, e.BibRangeEnd, e.AgeGroups,
+		e.RegistrationOpensAt, e.RegistrationClosesAt,
+		e.ID,
 	); err != nil {
-		// no rows → no es owner o no existe
 		return false, err
 	}
-	return true, nil
-}
 
-// Eliminar solo si el owner coincide
-func DeleteEventByOwner(eventID, ownerID int) (bool, error) {
-	const q = `
-		DELETE FROM events
-		WHERE id = $1 AND created_by = $2
-		RETURNING id
-	`
-	var id int
-	if err := config.DB.Get(&id, q, eventID, ownerID); err != nil {
+	if before.DateChanged || before.Location != e.Location {
+		notice := models.EventNotice{
+			EventID:   e.ID,
+			EventName: e.Name,
+			Kind:      models.NotificationEventUpdated,
+			Date:      e.Date,
+			Location:  e.Location,
+		}
+		if before.DateChanged {
+			notice.OldDate = &before.Date
+		}
+		if before.Location != e.Location {
+			notice.OldLocation = &before.Location
+		}
+		if err := enqueueEventNoticeTx(tx, notice); err != nil {
+			return false, err
+		}
+	}
+	if before.DateChanged {
+		if err := rescheduleRemindersTx(tx, e.ID, e.Date); err != nil {
+			return false, err
+		}
+	}
+
+	changes := map[string]interface{}{"name": e.Name, "date": e.Date, "location": e.Location}
+	if before.DateChanged {
+		changes["old_date"] = before.Date
+	}
+	if before.Location != e.Location {
+		changes["old_location"] = before.Location
+	}
+	if err := enqueueWebhooksTx(tx, e.ID, models.WebhookEventUpdated, changes); err != nil {
+		return false, err
+	}
+
+	if _, err := promoteFromWaitlist(tx, e.ID); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-// (Opcional) Validación simple de existencia del evento
-func MustOwnEvent(eventID, ownerID int) error {
-	const q = `SELECT 1 FROM events WHERE id=$1 AND created_by=$2`
+// ErrEventHasRegistrations: no se borra un evento con inscripciones (se borrarían en cascada)
+var ErrEventHasRegistrations = errors.New("el evento tiene inscripciones activas")
+
+// DeleteEvent elimina el evento si no tiene inscripciones; retorna (bool) si existía.
+// La fila del evento se bloquea para que nadie se inscriba entre el conteo y el borrado.
+func DeleteEvent(eventID int) (bool, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
 	var one int
-	if err := config.DB.Get(&one, q, eventID, ownerID); err != nil {
-		return errors.New("no eres dueño del evento o no existe")
+	if err := tx.Get(&one, `SELECT 1 FROM events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	var total int
+	if err := tx.Get(&total, `SELECT COUNT(*) FROM registrations WHERE event_id = $1`, eventID); err != nil {
+		return false, err
+	}
+	if total > 0 {
+		return false, ErrEventHasRegistrations
 	}
-	return nil
+	if _, err := tx.Exec(`DELETE FROM events WHERE id = $1`, eventID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
 }
 
 func GetRegistrationsForEvent(eventID int) ([]models.EventRegistrationUser, error) {
@@ -189,9 +382,13 @@ func GetRegistrationsForEvent(eventID int) ([]models.EventRegistrationUser, erro
 			r.id   AS registration_id,
 			u.id   AS user_id,
 			u.name AS user_name,
-			u.email AS user_email
+			u.email AS user_email,
+			r.bib_number AS bib_number,
+			c.id AS category_id,
+			c.name AS category_name
 		FROM registrations r
 		JOIN users u ON u.id = r.user_id
+		LEFT JOIN event_categories c ON c.id = r.category_id
 		WHERE r.event_id = $1
 		ORDER BY r.id DESC;
 	`
@@ -200,33 +397,62 @@ func GetRegistrationsForEvent(eventID int) ([]models.EventRegistrationUser, erro
 }
 
 
-// Filtros opcionales: type, location (parcial), date (YYYY-MM-DD)
-func GetEventsFiltered(eventType, location, date string,includeCancelled bool) ([]models.Event, error) {
+// Filtros opcionales para listar eventos
+type EventFilter struct {
+	Type             string
+	Location         string // coincidencia parcial
+	Date             string // YYYY-MM-DD
+	IncludeCancelled bool
+	MinDistanceM     *float64
+	MaxDistanceM     *float64
+	Status           string
+}
+
+func GetEventsFiltered(f EventFilter) ([]models.Event, error) {
 	var events []models.Event
 	query := `
-		SELECT id, name, description, type, date, location, route, created_by, created_at,status
+		SELECT id, name, description, type, date, location, route, created_by, created_at,status, capacity, checkin_mode, checkpoint_radius_m,
+			distance_m, elevation_gain_m, elevation_loss_m, bib_range_start, bib_range_end, age_groups,
+			registration_opens_at, registration_closes_at, results_finalized_at
 		FROM events
 		WHERE 1=1
 	`
 	args := []interface{}{}
 	i := 1
 
-	if eventType != "" {
+	if f.Type != "" {
 		query += fmt.Sprintf(" AND type = $%d", i)
-		args = append(args, eventType)
+		args = append(args, f.Type)
 		i++
 	}
-	if location != "" {
+	if f.Location != "" {
 		query += fmt.Sprintf(" AND location ILIKE $%d", i)
-		args = append(args, "%"+location+"%")
+		args = append(args, "%"+f.Location+"%")
 		i++
 	}
-	if date != "" {
+	if f.Date != "" {
 		query += fmt.Sprintf(" AND DATE(date) = $%d", i)
-		args = append(args, date) // formato YYYY-MM-DD
+		args = append(args, f.Date) // formato YYYY-MM-DD
+		i++
+	}
+	if f.MinDistanceM != nil {
+		query += fmt.Sprintf(" AND distance_m >= $%d", i)
+		args = append(args, *f.MinDistanceM)
 		i++
 	}
-	if !includeCancelled {
+	if f.MaxDistanceM != nil {
+		query += fmt.Sprintf(" AND distance_m <= $%d", i)
+		args = append(args, *f.MaxDistanceM)
+		i++
+	}
+	if f.Status != "" {
+		query += fmt.Sprintf(" AND status = $%d", i)
+		args = append(args, f.Status)
+		i++
+	}
+	// Los borradores solo los ve su organizador (GET /api/me)
+	query += " AND status <> 'draft'"
+	if !f.IncludeCancelled {
 		query += " AND status <> 'cancelled'"
 	}
 
@@ -237,23 +463,6 @@ func GetEventsFiltered(eventType, location, date string,includeCancelled bool) (
 	return events, err
 }
 
-// Cambiar estado a 'cancelled' solo si es owner
-func CancelEventByOwner(eventID, ownerID int, reason string) (bool, error) {
-	const q = `
-		UPDATE events
-		SET status = 'cancelled',
-		    cancelled_at = NOW(),
-		    cancellation_reason = $1
-		WHERE id = $2 AND created_by = $3 AND status <> 'cancelled'
-		RETURNING id
-	`
-	var id int
-	if err := config.DB.Get(&id, q, reason, eventID, ownerID); err != nil {
-		return false, err
-	}
-	return true, nil
-}
-
 // (útil para validaciones)
 func GetEventStatus(eventID int) (string, error) {
 	const q = `SELECT status FROM events WHERE id = $1`
@@ -262,10 +471,37 @@ func GetEventStatus(eventID int) (string, error) {
 	return st, err
 }
 
-func GetEventRoute(eventID int) (json.RawMessage, error) {
-    var route json.RawMessage
+// GetEventRoute devuelve la ruta del evento (nil si no tiene)
+func GetEventRoute(eventID int) (*models.Route, error) {
+    var route *models.Route
     const q = `SELECT route FROM events WHERE id = $1`
     err := config.DB.Get(&route, q, eventID)
     return route, err
 }
 
+
+// GetEventCheckinSettings devuelve el modo de orden y el radio por defecto de los checkpoints
+func GetEventCheckinSettings(eventID int) (models.EventCheckinSettings, error) {
+	const q = `SELECT status, checkin_mode, checkpoint_radius_m FROM events WHERE id = $1`
+	var st models.EventCheckinSettings
+	err := config.DB.Get(&st, q, eventID)
+	return st, err
+}
+
+// UpdateEventRoute reemplaza la ruta (y sus métricas); retorna (bool) si el evento existía
+func UpdateEventRoute(eventID int, route models.Route, m models.RouteMetrics) (bool, error) {
+	const q = `
+		UPDATE events
+		SET route = $1,
+		    distance_m = $2,
+		    elevation_gain_m = $3,
+		    elevation_loss_m = $4
+		WHERE id = $5
+	`
+	res, err := config.DB.Exec(q, route, m.DistanceM, m.ElevationGainM, m.ElevationLossM, eventID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/lifecycle"
+	"sport-events-backend/internal/models"
+)
+
+// TransitionEventStatus cambia el estado del evento si la transición es válida y la
+// registra en el historial. changedBy nil = cambio automático del sistema.
+// Cancelar o aplazar encola el aviso a los inscritos en la misma transacción, y todo
+// cambio encola los webhooks del organizador.
+func TransitionEventStatus(eventID int, to string, changedBy *int, reason string) (models.EventStatusTransition, error) {
+	var t models.EventStatusTransition
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return t, err
+	}
+	defer tx.Rollback()
+
+	var from string
+	if err := tx.Get(&from, `SELECT status FROM events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return t, ErrEventNotFound
+		}
+		return t, err
+	}
+	if err := lifecycle.CanTransition(from, to); err != nil {
+		return t, err
+	}
+
+	const updateQ = `
+		UPDATE events
+		SET status = $1,
+		    status_changed_at = NOW(),
+		    cancelled_at = CASE WHEN $1 = 'cancelled' THEN NOW() ELSE cancelled_at END,
+		    cancellation_reason = CASE WHEN $1 = 'cancelled' THEN $2 ELSE cancellation_reason END
+		WHERE id = $3
+	`
+	if _, err := tx.Exec(updateQ, to, nullIfEmpty(reason), eventID); err != nil {
+		return t, err
+	}
+
+	if kind, notify := noticeKinds[to]; notify {
+		notice, err := eventNoticeTx(tx, eventID, kind)
+		if err != nil {
+			return t, err
+		}
+		notice.Reason = nullIfEmpty(reason)
+		if err := enqueueEventNoticeTx(tx, notice); err != nil {
+			return t, err
+		}
+	}
+
+	change := models.WebhookStatusChange{From: from, To: to, Reason: nullIfEmpty(reason)}
+	if err := enqueueWebhooksTx(tx, eventID, models.WebhookEventStatusChanged, change); err != nil {
+		return t, err
+	}
+	if eventType, ok := webhookStatusTypes[to]; ok {
+		if err := enqueueWebhooksTx(tx, eventID, eventType, change); err != nil {
+			return t, err
+		}
+	}
+
+	const logQ = `
+		INSERT INTO event_status_transitions (event_id, from_status, to_status, changed_by, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, event_id, from_status, to_status, changed_by, reason, created_at
+	`
+	if err := tx.Get(&t, logQ, eventID, from, to, changedBy, nullIfEmpty(reason)); err != nil {
+		return t, err
+	}
+	return t, tx.Commit()
+}
+
+// GetEventStatusTransitions devuelve el historial de estados del evento (más antiguo primero)
+func GetEventStatusTransitions(eventID int) ([]models.EventStatusTransition, error) {
+	rows := []models.EventStatusTransition{}
+	const q = `
+		SELECT id, event_id, from_status, to_status, changed_by, reason, created_at
+		FROM event_status_transitions
+		WHERE event_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+	err := config.DB.Select(&rows, q, eventID)
+	return rows, err
+}
+
+// noticeKinds: estados que se notifican a los inscritos
+var noticeKinds = map[string]string{
+	lifecycle.StatusCancelled: models.NotificationEventCancelled,
+	lifecycle.StatusPostponed: models.NotificationEventPostponed,
+}
+
+// webhookStatusTypes: estados con un tipo de webhook propio, además de event.status_changed
+var webhookStatusTypes = map[string]string{
+	lifecycle.StatusCancelled: models.WebhookEventCancelled,
+	lifecycle.StatusPostponed: models.WebhookEventPostponed,
+}
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
+)
+
+// insertInboxTx deja la notificación en la bandeja del usuario (ya entregada)
+func insertInboxTx(tx *sqlx.Tx, eventID, userID int, kind string, payload []byte) error {
+	const q = `
+		INSERT INTO notifications (event_id, user_id, channel, kind, recipient, payload, locale, status, sent_at)
+		SELECT $1, u.id, $3, $4, u.email, $5, u.locale, 'sent', NOW()
+		FROM users u
+		WHERE u.id = $2
+	`
+	_, err := tx.Exec(q, eventID, userID, models.NotificationChannelInbox, kind, payload)
+	return err
+}
+
+// GetUserInbox devuelve la bandeja del usuario (más recientes primero)
+func GetUserInbox(userID int, unreadOnly bool, limit int) ([]models.Notification, error) {
+	rows := []models.Notification{}
+	const q = `
+		SELECT * FROM notifications
+		WHERE user_id = $1 AND channel = 'inbox'
+		  AND ($2 = FALSE OR read_at IS NULL)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+	err := config.DB.Select(&rows, q, userID, unreadOnly, limit)
+	return rows, err
+}
+
+func CountUnreadInbox(userID int) (int, error) {
+	var n int
+	const q = `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND channel = 'inbox' AND read_at IS NULL`
+	err := config.DB.Get(&n, q, userID)
+	return n, err
+}
+
+// MarkInboxRead marca como leída una notificación de la bandeja del usuario
+func MarkInboxRead(userID, notificationID int) (bool, error) {
+	const q = `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2 AND channel = 'inbox'
+	`
+	res, err := config.DB.Exec(q, notificationID, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// MarkAllInboxRead devuelve cuántas quedaron marcadas
+func MarkAllInboxRead(userID int) (int64, error) {
+	const q = `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND channel = 'inbox' AND read_at IS NULL`
+	res, err := config.DB.Exec(q, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetNotificationPreferences devuelve las preferencias guardadas o las de por defecto
+func GetNotificationPreferences(userID int) (models.NotificationPreferences, error) {
+	var p models.NotificationPreferences
+	err := config.DB.Get(&p, `SELECT * FROM notification_preferences WHERE user_id = $1`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.DefaultNotificationPreferences(userID), nil
+	}
+	return p, err
+}
+
+func SaveNotificationPreferences(p models.NotificationPreferences) (models.NotificationPreferences, error) {
+	var out models.NotificationPreferences
+	const q = `
+		INSERT INTO notification_preferences (user_id, email_reminders, email_event_updates, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET email_reminders = EXCLUDED.email_reminders,
+		    email_event_updates = EXCLUDED.email_event_updates,
+		    updated_at = NOW()
+		RETURNING *
+	`
+	err := config.DB.Get(&out, q, p.UserID, p.EmailReminders, p.EmailEventUpdates)
+	return out, err
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"time"
+
+	"sport-events-backend/internal/config"
+)
+
+// WithJobLock ejecuta fn solo si esta réplica consigue el advisory lock del job.
+// El lock se mantiene en una transacción abierta mientras corre fn, así que si otra
+// réplica ya lo está ejecutando se omite (ran = false) en lugar de duplicar el trabajo.
+func WithJobLock(name string, fn func() error) (ran bool, err error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.Get(&locked, `SELECT pg_try_advisory_xact_lock(hashtext('jobs'), hashtext($1))`, name); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	if err := fn(); err != nil {
+		return true, err
+	}
+	return true, tx.Commit()
+}
+
+// GetEventsToOpenRegistration: publicados cuya ventana de inscripción ya empezó (y no terminó)
+func GetEventsToOpenRegistration() ([]int, error) {
+	var ids []int
+	const q = `
+		SELECT id FROM events
+		WHERE status = 'published'
+		  AND registration_opens_at IS NOT NULL
+		  AND registration_opens_at <= NOW()
+		  AND COALESCE(registration_closes_at, date) > NOW()
+		ORDER BY id
+	`
+	err := config.DB.Select(&ids, q)
+	return ids, err
+}
+
+// GetEventsToCloseRegistration: inscripciones abiertas cuyo cierre (o el inicio del evento) ya pasó
+func GetEventsToCloseRegistration() ([]int, error) {
+	var ids []int
+	const q = `
+		SELECT id FROM events
+		WHERE status = 'registration_open'
+		  AND COALESCE(registration_closes_at, date) <= NOW()
+		ORDER BY id
+	`
+	err := config.DB.Select(&ids, q)
+	return ids, err
+}
+
+// GetEventsToComplete: eventos cerrados o en curso cuya fecha pasó hace más de grace
+func GetEventsToComplete(grace time.Duration) ([]int, error) {
+	var ids []int
+	const q = `
+		SELECT id FROM events
+		WHERE status IN ('registration_closed', 'live')
+		  AND date <= NOW() - ($1 * INTERVAL '1 second')
+		ORDER BY id
+	`
+	err := config.DB.Select(&ids, q, int64(grace.Seconds()))
+	return ids, err
+}
+
+// GetEventsToFinalizeResults: eventos terminados con resultados aún sin cerrar
+func GetEventsToFinalizeResults() ([]int, error) {
+	var ids []int
+	const q = `
+		SELECT id FROM events
+		WHERE status = 'completed' AND results_finalized_at IS NULL
+		ORDER BY id
+	`
+	err := config.DB.Select(&ids, q)
+	return ids, err
+}
+
+func MarkResultsFinalized(eventID int) error {
+	_, err := config.DB.Exec(`UPDATE events SET results_finalized_at = NOW() WHERE id = $1`, eventID)
+	return err
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
+)
+
+var (
+	ErrMemberNotFound          = errors.New("el usuario no es miembro del equipo del evento")
+	ErrOwnerMember             = errors.New("el owner del evento no se puede modificar ni quitar")
+	ErrAlreadyMember           = errors.New("el usuario ya es miembro del equipo del evento")
+	ErrInvalidInvitation       = errors.New("la invitación no es válida o expiró")
+	ErrInvitationEmailMismatch = errors.New("la invitación es para otro email")
+)
+
+func addEventMemberTx(tx *sqlx.Tx, eventID, userID int, role string, addedBy *int) error {
+	const q = `INSERT INTO event_members (event_id, user_id, role, added_by) VALUES ($1, $2, $3, $4)`
+	_, err := tx.Exec(q, eventID, userID, role, addedBy)
+	if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+		return ErrAlreadyMember
+	}
+	return err
+}
+
+// syncMemberStaffTx mantiene la asignación a todo el evento (event_staff sin checkpoint) de
+// los miembros con rol staff: los permisos de checkin del staff salen de event_staff, así
+// que el miembro staff aparece en GET /events/{id}/staff y se gestiona como el resto.
+func syncMemberStaffTx(tx *sqlx.Tx, eventID, userID int, role string, assignedBy int) error {
+	if role != models.MemberStaff {
+		_, err := tx.Exec(`DELETE FROM event_staff WHERE event_id = $1 AND user_id = $2 AND checkpoint_id IS NULL`, eventID, userID)
+		return err
+	}
+	const q = `
+		INSERT INTO event_staff (event_id, user_id, checkpoint_id, assigned_by)
+		VALUES ($1, $2, NULL, $3)
+		ON CONFLICT (event_id, user_id, COALESCE(checkpoint_id, 0)) DO NOTHING
+	`
+	_, err := tx.Exec(q, eventID, userID, assignedBy)
+	return err
+}
+
+// GetEventMembers lista el equipo del evento (el owner primero)
+func GetEventMembers(eventID int) ([]models.EventMember, error) {
+	rows := []models.EventMember{}
+	const q = `
+		SELECT
+			m.id,
+			m.event_id,
+			m.user_id,
+			u.name  AS user_name,
+			u.email AS user_email,
+			m.role,
+			m.added_by,
+			m.created_at
+		FROM event_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.event_id = $1
+		ORDER BY m.role = 'owner' DESC, u.name
+	`
+	err := config.DB.Select(&rows, q, eventID)
+	return rows, err
+}
+
+// GetMemberEvents lista los eventos en cuyo equipo está el usuario sin ser el owner
+func GetMemberEvents(userID int) ([]models.MemberEvent, error) {
+	rows := []models.MemberEvent{}
+	const q = `
+		SELECT e.id, e.name, e.type, e.date, e.location, e.created_by, e.status, m.role AS member_role
+		FROM event_members m
+		JOIN events e ON e.id = m.event_id
+		WHERE m.user_id = $1 AND m.role <> 'owner'
+		ORDER BY e.date DESC
+	`
+	err := config.DB.Select(&rows, q, userID)
+	return rows, err
+}
+
+// memberRoleTx devuelve el rol del usuario en el equipo bloqueando la fila
+// (ErrMemberNotFound si no es miembro)
+func memberRoleTx(tx *sqlx.Tx, eventID, userID int) (string, error) {
+	var role string
+	err := tx.Get(&role, `SELECT role FROM event_members WHERE event_id = $1 AND user_id = $2 FOR UPDATE`, eventID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrMemberNotFound
+	}
+	return role, err
+}
+
+// SetEventMemberRole cambia el rol de un miembro; el owner no se puede cambiar.
+// Pasar a staff (o dejar de serlo) crea (o quita) su asignación a todo el evento en event_staff.
+func SetEventMemberRole(eventID, userID int, role string, changedBy int) error {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := memberRoleTx(tx, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if current == models.MemberOwner {
+		return ErrOwnerMember
+	}
+	const q = `UPDATE event_members SET role = $1 WHERE event_id = $2 AND user_id = $3 AND role <> 'owner'`
+	if _, err := tx.Exec(q, role, eventID, userID); err != nil {
+		return err
+	}
+	if current == models.MemberStaff || role == models.MemberStaff {
+		if err := syncMemberStaffTx(tx, eventID, userID, role, changedBy); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// RemoveEventMember quita a un miembro del equipo; el owner no se puede quitar.
+// A un miembro staff se le quita también su asignación a todo el evento.
+func RemoveEventMember(eventID, userID int) error {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := memberRoleTx(tx, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if current == models.MemberOwner {
+		return ErrOwnerMember
+	}
+	if _, err := tx.Exec(`DELETE FROM event_members WHERE event_id = $1 AND user_id = $2 AND role <> 'owner'`, eventID, userID); err != nil {
+		return err
+	}
+	if current == models.MemberStaff {
+		if err := syncMemberStaffTx(tx, eventID, userID, "", 0); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CreateEventInvitation guarda la invitación y devuelve su ID. Una invitación pendiente
+// para el mismo email se reemplaza (el enlace anterior deja de valer).
+// Si el email ya es de un miembro del equipo devuelve ErrAlreadyMember.
+func CreateEventInvitation(eventID int, email, role, tokenHash string, invitedBy int, expiresAt time.Time) (int, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var member bool
+	const memberQ = `
+		SELECT EXISTS(
+			SELECT 1 FROM event_members m
+			JOIN users u ON u.id = m.user_id
+			WHERE m.event_id = $1 AND LOWER(u.email) = LOWER($2)
+		)
+	`
+	if err := tx.Get(&member, memberQ, eventID, email); err != nil {
+		return 0, err
+	}
+	if member {
+		return 0, ErrAlreadyMember
+	}
+
+	const deleteQ = `DELETE FROM event_invitations WHERE event_id = $1 AND LOWER(email) = LOWER($2) AND accepted_at IS NULL`
+	if _, err := tx.Exec(deleteQ, eventID, email); err != nil {
+		return 0, err
+	}
+	const q = `
+		INSERT INTO event_invitations (event_id, email, role, token_hash, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	var id int
+	if err := tx.Get(&id, q, eventID, strings.TrimSpace(email), role, tokenHash, invitedBy, expiresAt); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// GetPendingEventInvitations lista las invitaciones del evento aún no aceptadas
+func GetPendingEventInvitations(eventID int) ([]models.EventInvitation, error) {
+	rows := []models.EventInvitation{}
+	const q = `
+		SELECT id, event_id, email, role, invited_by, expires_at, accepted_at, accepted_by, created_at
+		FROM event_invitations
+		WHERE event_id = $1 AND accepted_at IS NULL
+		ORDER BY created_at DESC
+	`
+	err := config.DB.Select(&rows, q, eventID)
+	return rows, err
+}
+
+// DeleteEventInvitation anula una invitación pendiente; retorna (bool) si eliminó algo
+func DeleteEventInvitation(eventID, invitationID int) (bool, error) {
+	const q = `DELETE FROM event_invitations WHERE id = $1 AND event_id = $2 AND accepted_at IS NULL`
+	res, err := config.DB.Exec(q, invitationID, eventID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// AcceptEventInvitation consume la invitación y suma al usuario al equipo con el rol invitado.
+// El email de la cuenta debe coincidir con el de la invitación.
+func AcceptEventInvitation(tokenHash string, userID int) (models.EventInvitation, error) {
+	var inv models.EventInvitation
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return inv, err
+	}
+	defer tx.Rollback()
+
+	const lockQ = `
+		SELECT i.id, i.event_id, e.name AS event_name, i.email, i.role, i.invited_by,
+		       i.expires_at, i.accepted_at, i.accepted_by, i.created_at
+		FROM event_invitations i
+		JOIN events e ON e.id = i.event_id
+		WHERE i.token_hash = $1 AND i.accepted_at IS NULL AND i.expires_at > NOW()
+		FOR UPDATE OF i
+	`
+	if err := tx.Get(&inv, lockQ, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return inv, ErrInvalidInvitation
+		}
+		return inv, err
+	}
+
+	var email string
+	if err := tx.Get(&email, `SELECT email FROM users WHERE id = $1`, userID); err != nil {
+		return inv, err
+	}
+	if !strings.EqualFold(email, inv.Email) {
+		return inv, ErrInvitationEmailMismatch
+	}
+
+	if err := addEventMemberTx(tx, inv.EventID, userID, inv.Role, &inv.InvitedBy); err != nil {
+		return inv, err
+	}
+	if inv.Role == models.MemberStaff {
+		if err := syncMemberStaffTx(tx, inv.EventID, userID, inv.Role, inv.InvitedBy); err != nil {
+			return inv, err
+		}
+	}
+	const acceptQ = `UPDATE event_invitations SET accepted_at = NOW(), accepted_by = $2 WHERE id = $1 RETURNING accepted_at`
+	if err := tx.Get(&inv.AcceptedAt, acceptQ, inv.ID, userID); err != nil {
+		return inv, err
+	}
+	inv.AcceptedBy = &userID
+	return inv, tx.Commit()
+}
+
+// PurgeEventInvitations borra las invitaciones aceptadas o vencidas hace más de olderThan
+func PurgeEventInvitations(olderThan time.Duration) (int64, error) {
+	const q = `
+		DELETE FROM event_invitations
+		WHERE COALESCE(accepted_at, expires_at) < NOW() - ($1 * INTERVAL '1 second')
+	`
+	res, err := config.DB.Exec(q, int64(olderThan.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
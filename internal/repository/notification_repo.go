@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
+)
+
+// enqueueEventNoticeTx deja el aviso en la bandeja de cada inscrito, escribe en el outbox
+// el email para los que no lo desactivaron y, si hay NOTIFICATIONS_WEBHOOK_URL, una
+// notificación para el webhook. Va en la transacción del cambio que la origina: si el
+// cambio no se confirma, no se notifica nada.
+func enqueueEventNoticeTx(tx *sqlx.Tx, notice models.EventNotice) error {
+	payload, err := json.Marshal(notice)
+	if err != nil {
+		return err
+	}
+
+	const inboxQ = `
+		INSERT INTO notifications (event_id, user_id, channel, kind, recipient, payload, locale, status, sent_at)
+		SELECT r.event_id, u.id, $2, $3, u.email, $4, u.locale, 'sent', NOW()
+		FROM registrations r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.event_id = $1
+	`
+	if _, err := tx.Exec(inboxQ, notice.EventID, models.NotificationChannelInbox, notice.Kind, payload); err != nil {
+		return err
+	}
+
+	const emailQ = `
+		INSERT INTO notifications (event_id, user_id, channel, kind, recipient, payload, locale)
+		SELECT r.event_id, u.id, $2, $3, u.email, $4, u.locale
+		FROM registrations r
+		JOIN users u ON u.id = r.user_id
+		LEFT JOIN notification_preferences p ON p.user_id = u.id
+		WHERE r.event_id = $1 AND COALESCE(p.email_event_updates, TRUE)
+	`
+	if _, err := tx.Exec(emailQ, notice.EventID, models.NotificationChannelEmail, notice.Kind, payload); err != nil {
+		return err
+	}
+
+	if url := os.Getenv("NOTIFICATIONS_WEBHOOK_URL"); url != "" {
+		const webhookQ = `
+			INSERT INTO notifications (event_id, channel, kind, recipient, payload)
+			VALUES ($1, $2, $3, $4, $5)
+		`
+		if _, err := tx.Exec(webhookQ, notice.EventID, models.NotificationChannelWebhook, notice.Kind, url, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueueRegistrationConfirmedTx encola el email de confirmación de una inscripción
+// (directa o promovida desde la lista de espera)
+func enqueueRegistrationConfirmedTx(tx *sqlx.Tx, userID, eventID int, bib *int) error {
+	var notice models.RegistrationNotice
+	const q = `
+		SELECT e.id AS event_id, e.name AS event_name, e.date, e.location, u.name AS user_name, c.name AS category_name
+		FROM registrations r
+		JOIN events e ON e.id = r.event_id
+		JOIN users u ON u.id = r.user_id
+		LEFT JOIN event_categories c ON c.id = r.category_id
+		WHERE r.user_id = $1 AND r.event_id = $2
+	`
+	if err := tx.Get(&notice, q, userID, eventID); err != nil {
+		return err
+	}
+	notice.BibNumber = bib
+	payload, err := json.Marshal(notice)
+	if err != nil {
+		return err
+	}
+
+	const insertQ = `
+		INSERT INTO notifications (event_id, user_id, channel, kind, recipient, payload, locale)
+		SELECT $1, u.id, $3, $4, u.email, $5, u.locale
+		FROM users u
+		WHERE u.id = $2
+	`
+	_, err = tx.Exec(insertQ, eventID, userID, models.NotificationChannelEmail, models.NotificationRegistrationConfirmed, payload)
+	return err
+}
+
+// eventNoticeTx arma el aviso con los datos actuales del evento
+func eventNoticeTx(tx *sqlx.Tx, eventID int, kind string) (models.EventNotice, error) {
+	n := models.EventNotice{EventID: eventID, Kind: kind}
+	var row struct {
+		Name     string    `db:"name"`
+		Date     time.Time `db:"date"`
+		Location string    `db:"location"`
+	}
+	if err := tx.Get(&row, `SELECT name, date, location FROM events WHERE id = $1`, eventID); err != nil {
+		return n, err
+	}
+	n.EventName, n.Date, n.Location = row.Name, row.Date, row.Location
+	return n, nil
+}
+
+// ClaimDueNotifications toma hasta limit notificaciones pendientes y las marca como
+// "sending" con un lease: si el proceso muere, vuelven a estar disponibles al vencer.
+// Las que vencieron el lease sin intentos restantes pasan a "failed" en vez de reintentarse.
+func ClaimDueNotifications(limit int, lease time.Duration) ([]models.Notification, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	const exhaustedQ = `
+		UPDATE notifications
+		SET status = 'failed',
+		    last_error = COALESCE(last_error, 'lease vencido sin intentos restantes')
+		WHERE status = 'sending' AND next_attempt_at <= NOW() AND attempts >= max_attempts
+	`
+	if _, err := tx.Exec(exhaustedQ); err != nil {
+		return nil, err
+	}
+
+	var rows []models.Notification
+	const q = `
+		UPDATE notifications
+		SET status = 'sending',
+		    attempts = attempts + 1,
+		    next_attempt_at = NOW() + ($2 * INTERVAL '1 second')
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE status IN ('pending', 'sending') AND next_attempt_at <= NOW()
+			  AND attempts < max_attempts
+			ORDER BY next_attempt_at ASC, id ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+	if err := tx.Select(&rows, q, limit, int64(lease.Seconds())); err != nil {
+		return nil, err
+	}
+	return rows, tx.Commit()
+}
+
+func MarkNotificationSent(id int) error {
+	const q = `UPDATE notifications SET status = 'sent', sent_at = NOW(), last_error = NULL WHERE id = $1`
+	_, err := config.DB.Exec(q, id)
+	return err
+}
+
+// MarkNotificationFailed programa un reintento o, si se agotaron, la deja en "failed"
+func MarkNotificationFailed(n models.Notification, cause error, retryIn time.Duration) error {
+	const q = `
+		UPDATE notifications
+		SET status = CASE WHEN attempts >= max_attempts THEN 'failed' ELSE 'pending' END,
+		    next_attempt_at = NOW() + ($2 * INTERVAL '1 second'),
+		    last_error = $3
+		WHERE id = $1
+	`
+	_, err := config.DB.Exec(q, n.ID, int64(retryIn.Seconds()), cause.Error())
+	return err
+}
+
+// GetEventNotifications lista las notificaciones del evento que pasan por el outbox
+// (más recientes primero); las copias de la bandeja no se incluyen
+func GetEventNotifications(eventID int) ([]models.Notification, error) {
+	rows := []models.Notification{}
+	const q = `SELECT * FROM notifications WHERE event_id = $1 AND channel <> 'inbox' ORDER BY created_at DESC, id DESC`
+	err := config.DB.Select(&rows, q, eventID)
+	return rows, err
+}
+
+// CountEventNotificationsByStatus devuelve el total por estado de entrega
+func CountEventNotificationsByStatus(eventID int) (map[string]int, error) {
+	var rows []struct {
+		Status string `db:"status"`
+		Total  int    `db:"total"`
+	}
+	const q = `SELECT status, COUNT(*) AS total FROM notifications WHERE event_id = $1 AND channel <> 'inbox' GROUP BY status`
+	if err := config.DB.Select(&rows, q, eventID); err != nil {
+		return nil, err
+	}
+	counts := map[string]int{
+		models.NotificationStatusPending: 0,
+		models.NotificationStatusSending: 0,
+		models.NotificationStatusSent:    0,
+		models.NotificationStatusFailed:  0,
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Total
+	}
+	return counts, nil
+}
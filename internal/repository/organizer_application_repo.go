@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
+)
+
+var (
+	ErrApplicationNotFound = errors.New("solicitud no encontrada")
+	ErrApplicationPending  = errors.New("ya tienes una solicitud pendiente")
+	ErrApplicationReviewed = errors.New("la solicitud ya fue revisada")
+)
+
+const applicationSelect = `
+	SELECT a.*, u.name AS user_name, u.email AS user_email
+	FROM organizer_applications a
+	JOIN users u ON u.id = a.user_id
+`
+
+func CreateOrganizerApplication(userID int, organization, message string) (models.OrganizerApplication, error) {
+	var id int
+	const q = `
+		INSERT INTO organizer_applications (user_id, organization, message)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+	if err := config.DB.Get(&id, q, userID, nullIfEmpty(organization), nullIfEmpty(message)); err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			return models.OrganizerApplication{}, ErrApplicationPending
+		}
+		return models.OrganizerApplication{}, err
+	}
+	return GetOrganizerApplication(id)
+}
+
+func GetOrganizerApplication(id int) (models.OrganizerApplication, error) {
+	var a models.OrganizerApplication
+	err := config.DB.Get(&a, applicationSelect+` WHERE a.id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return a, ErrApplicationNotFound
+	}
+	return a, err
+}
+
+// GetLatestOrganizerApplication devuelve la última solicitud del usuario
+func GetLatestOrganizerApplication(userID int) (models.OrganizerApplication, error) {
+	var a models.OrganizerApplication
+	err := config.DB.Get(&a, applicationSelect+` WHERE a.user_id = $1 ORDER BY a.created_at DESC, a.id DESC LIMIT 1`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return a, ErrApplicationNotFound
+	}
+	return a, err
+}
+
+// GetOrganizerApplications lista las solicitudes (status vacío = todas), las más antiguas primero
+func GetOrganizerApplications(status string) ([]models.OrganizerApplication, error) {
+	rows := []models.OrganizerApplication{}
+	const where = ` WHERE ($1 = '' OR a.status = $1) ORDER BY a.created_at ASC, a.id ASC`
+	err := config.DB.Select(&rows, applicationSelect+where, status)
+	return rows, err
+}
+
+// ReviewOrganizerApplication aprueba o rechaza una solicitud pendiente. Al aprobarla el
+// usuario pasa a organizer (un admin conserva su rol).
+func ReviewOrganizerApplication(id, reviewerID int, approve bool, note string) (models.OrganizerApplication, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return models.OrganizerApplication{}, err
+	}
+	defer tx.Rollback()
+
+	var row struct {
+		UserID int    `db:"user_id"`
+		Status string `db:"status"`
+	}
+	if err := tx.Get(&row, `SELECT user_id, status FROM organizer_applications WHERE id = $1 FOR UPDATE`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.OrganizerApplication{}, ErrApplicationNotFound
+		}
+		return models.OrganizerApplication{}, err
+	}
+	if row.Status != models.ApplicationPending {
+		return models.OrganizerApplication{}, ErrApplicationReviewed
+	}
+
+	status := models.ApplicationRejected
+	if approve {
+		status = models.ApplicationApproved
+	}
+	const q = `
+		UPDATE organizer_applications
+		SET status = $1, reviewed_by = $2, reviewed_at = NOW(), review_note = $3
+		WHERE id = $4
+	`
+	if _, err := tx.Exec(q, status, reviewerID, nullIfEmpty(note), id); err != nil {
+		return models.OrganizerApplication{}, err
+	}
+	if approve {
+		const roleQ = `UPDATE users SET role = $1 WHERE id = $2 AND role = $3`
+		if _, err := tx.Exec(roleQ, models.RoleOrganizer, row.UserID, models.RoleRunner); err != nil {
+			return models.OrganizerApplication{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return models.OrganizerApplication{}, err
+	}
+	return GetOrganizerApplication(id)
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
+)
+
+var ErrInvalidResetToken = errors.New("el enlace de restablecimiento no es válido o expiró")
+
+// CountRecentPasswordResets cuenta las solicitudes del usuario desde since (rate limit por email)
+func CountRecentPasswordResets(userID int, since time.Time) (int, error) {
+	var n int
+	const q = `SELECT COUNT(*) FROM password_reset_tokens WHERE user_id = $1 AND created_at >= $2`
+	err := config.DB.Get(&n, q, userID, since)
+	return n, err
+}
+
+func CreatePasswordResetToken(userID int, tokenHash string, expiresAt time.Time, ip string) error {
+	const q = `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at, requested_ip)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := config.DB.Exec(q, userID, tokenHash, expiresAt, nullIfEmpty(ip))
+	return err
+}
+
+// ResetPasswordWithToken consume el token (si está vigente y sin usar), cambia la contraseña
+// e invalida el resto de tokens pendientes del usuario y todas sus sesiones.
+// Devuelve el ID del usuario.
+func ResetPasswordWithToken(tokenHash, passwordHash string) (int, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var userID int
+	const lockQ = `
+		SELECT user_id FROM password_reset_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		FOR UPDATE
+	`
+	if err := tx.Get(&userID, lockQ, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrInvalidResetToken
+		}
+		return 0, err
+	}
+
+	const userQ = `UPDATE users SET password = $1, password_changed_at = NOW() WHERE id = $2`
+	if _, err := tx.Exec(userQ, passwordHash, userID); err != nil {
+		return 0, err
+	}
+	const usedQ = `UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`
+	if _, err := tx.Exec(usedQ, userID); err != nil {
+		return 0, err
+	}
+	if _, err := revokeUserSessionsTx(tx, userID, models.SessionRevokedPasswordReset); err != nil {
+		return 0, err
+	}
+	return userID, tx.Commit()
+}
+
+// PurgePasswordResetTokens borra los tokens usados o vencidos hace más de olderThan
+func PurgePasswordResetTokens(olderThan time.Duration) (int64, error) {
+	const q = `
+		DELETE FROM password_reset_tokens
+		WHERE COALESCE(used_at, expires_at) < NOW() - ($1 * INTERVAL '1 second')
+	`
+	res, err := config.DB.Exec(q, int64(olderThan.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
+)
+
+// GetEventAccess reúne en una consulta todo lo que determina los permisos del usuario
+// sobre el evento: su rol en el equipo, los permisos concedidos y sus asignaciones de staff.
+// Exists es false si el evento no existe.
+func GetEventAccess(eventID, userID int) (models.EventAccess, error) {
+	var access models.EventAccess
+	const q = `
+		SELECT
+			TRUE AS event_exists,
+			COALESCE((
+				SELECT m.role FROM event_members m
+				WHERE m.event_id = e.id AND m.user_id = $2
+			), '') AS member_role,
+			ARRAY(
+				SELECT g.permission FROM event_permission_grants g
+				WHERE g.event_id = e.id AND g.user_id = $2
+			) AS grants,
+			EXISTS(
+				SELECT 1 FROM event_staff s
+				WHERE s.event_id = e.id AND s.user_id = $2 AND s.checkpoint_id IS NULL
+			) AS staff_event_wide,
+			ARRAY(
+				SELECT s.checkpoint_id FROM event_staff s
+				WHERE s.event_id = e.id AND s.user_id = $2 AND s.checkpoint_id IS NOT NULL
+			) AS staff_checkpoints
+		FROM events e
+		WHERE e.id = $1
+	`
+	rows, err := config.DB.Queryx(q, eventID, userID)
+	if err != nil {
+		return access, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		if err := rows.StructScan(&access); err != nil {
+			return access, err
+		}
+	}
+	return access, rows.Err()
+}
+
+// GrantEventPermission concede el permiso y devuelve el ID de la concesión
+func GrantEventPermission(eventID, userID int, permission string, grantedBy int) (int, error) {
+	const q = `
+		INSERT INTO event_permission_grants (event_id, user_id, permission, granted_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	var id int
+	err := config.DB.Get(&id, q, eventID, userID, permission, grantedBy)
+	return id, err
+}
+
+// RevokeEventPermission elimina una concesión; retorna (bool) si eliminó algo
+func RevokeEventPermission(eventID, grantID int) (bool, error) {
+	res, err := config.DB.Exec(`DELETE FROM event_permission_grants WHERE id = $1 AND event_id = $2`, grantID, eventID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func GetEventPermissionGrants(eventID int) ([]models.EventPermissionGrant, error) {
+	rows := []models.EventPermissionGrant{}
+	const q = `
+		SELECT
+			g.id,
+			g.event_id,
+			g.user_id,
+			u.name  AS user_name,
+			u.email AS user_email,
+			g.permission,
+			g.granted_by,
+			g.created_at
+		FROM event_permission_grants g
+		JOIN users u ON u.id = g.user_id
+		WHERE g.event_id = $1
+		ORDER BY u.name, g.permission
+	`
+	err := config.DB.Select(&rows, q, eventID)
+	return rows, err
+}
@@ -1,6 +1,23 @@
 package repository
 
-import "sport-events-backend/internal/config"
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/lifecycle"
+	"sport-events-backend/internal/models"
+)
+
+var (
+	ErrEventNotFound        = errors.New("evento no encontrado")
+	ErrAlreadyRegistered    = errors.New("ya estabas inscrito en este evento")
+	ErrAlreadyWaitlisted    = errors.New("ya estabas en la lista de espera de este evento")
+	ErrRegistrationNotFound = errors.New("inscripción no encontrada")
+)
 
 func CountRegistrationsForEvent(eventID int) (int, error) {
 	const q = `SELECT COUNT(*) FROM registrations WHERE event_id = $1`
@@ -9,12 +26,256 @@ func CountRegistrationsForEvent(eventID int) (int, error) {
 	return total, err
 }
 
-func CancelRegistration(userID, eventID int) error {
-    query := `
-        DELETE FROM registrations
-        WHERE user_id = $1 AND event_id = $2
-    `
-    _, err := config.DB.Exec(query, userID, eventID)
-    return err
+// CancelRegistration elimina la inscripción (o la entrada en lista de espera) del usuario.
+// Si se libera un cupo, el primer usuario en lista de espera se inscribe en la misma transacción.
+// Devuelve los IDs de usuarios promovidos.
+func CancelRegistration(userID, eventID int) ([]int, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Bloquear el evento para serializar con nuevas inscripciones
+	var status string
+	if err := tx.Get(&status, `SELECT status FROM events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrEventNotFound
+		}
+		return nil, err
+	}
+	if err := lifecycle.CheckRegistrationCancel(status); err != nil {
+		return nil, err
+	}
+
+	// Datos para el webhook antes de borrar (sin filas = no estaba inscrito)
+	data, dataErr := registrationWebhookDataTx(tx, userID, eventID)
+	if dataErr != nil && !errors.Is(dataErr, sql.ErrNoRows) {
+		return nil, dataErr
+	}
+
+	res, err := tx.Exec(`DELETE FROM registrations WHERE user_id = $1 AND event_id = $2`, userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	var promoted []int
+	if n == 0 {
+		// No estaba inscrito: quizá estaba en lista de espera
+		if _, err := tx.Exec(`DELETE FROM waitlist WHERE user_id = $1 AND event_id = $2`, userID, eventID); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := enqueueWebhooksTx(tx, eventID, models.WebhookRegistrationCancelled, data); err != nil {
+			return nil, err
+		}
+		promoted, err = promoteFromWaitlist(tx, eventID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return promoted, nil
+}
+
+// promoteFromWaitlist mueve usuarios de la lista de espera a inscripciones (orden de llegada)
+// mientras haya cupos. Con categorías, se promueve al primero cuya categoría tenga cupo.
+// Debe llamarse con la fila del evento bloqueada.
+func promoteFromWaitlist(tx *sqlx.Tx, eventID int) ([]int, error) {
+	var promoted []int
+	for {
+		var entries []struct {
+			ID         int  `db:"id"`
+			UserID     int  `db:"user_id"`
+			CategoryID *int `db:"category_id"`
+		}
+		const q = `
+			SELECT id, user_id, category_id FROM waitlist
+			WHERE event_id = $1
+			ORDER BY created_at ASC, id ASC
+		`
+		if err := tx.Select(&entries, q, eventID); err != nil {
+			return nil, err
+		}
+
+		moved := false
+		for _, entry := range entries {
+			room, err := hasRoomTx(tx, eventID, entry.CategoryID)
+			if err != nil {
+				return nil, err
+			}
+			if !room {
+				continue
+			}
+			if _, err := tx.Exec(`DELETE FROM waitlist WHERE id = $1`, entry.ID); err != nil {
+				return nil, err
+			}
+			if _, err := insertRegistrationTx(tx, entry.UserID, eventID, entry.CategoryID); err != nil {
+				return nil, err
+			}
+			promoted = append(promoted, entry.UserID)
+			moved = true
+			break
+		}
+		if !moved {
+			return promoted, nil // lista vacía o sin cupos
+		}
+	}
+}
+
+// insertRegistrationTx crea la inscripción con el siguiente dorsal libre del rango del evento
+// (o de la categoría, si define uno propio).
+// Debe llamarse con la fila del evento bloqueada para que la numeración sea secuencial.
+// Si el rango está agotado la inscripción queda sin dorsal (el organizador puede asignarlo).
+// La confirmación por email y el webhook registration.created se encolan en la misma transacción.
+func insertRegistrationTx(tx *sqlx.Tx, userID, eventID int, categoryID *int) (*int, error) {
+	bib, err := nextBibTx(tx, eventID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	const q = `INSERT INTO registrations (user_id, event_id, date, bib_number, category_id) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.Exec(q, userID, eventID, time.Now(), bib, categoryID); err != nil {
+		return nil, err
+	}
+	if err := enqueueRegistrationConfirmedTx(tx, userID, eventID, bib); err != nil {
+		return nil, err
+	}
+	data, err := registrationWebhookDataTx(tx, userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := enqueueWebhooksTx(tx, eventID, models.WebhookRegistrationCreated, data); err != nil {
+		return nil, err
+	}
+	return bib, nil
 }
 
+// nextBibTx devuelve el dorsal siguiente al mayor usado dentro del rango; si el rango
+// llegó al tope, busca el primer hueco libre (dorsales liberados por cancelaciones).
+func nextBibTx(tx *sqlx.Tx, eventID int, categoryID *int) (*int, error) {
+	var rng struct {
+		Start sql.NullInt64 `db:"bib_range_start"`
+		End   sql.NullInt64 `db:"bib_range_end"`
+	}
+	if categoryID != nil {
+		const q = `SELECT bib_range_start, bib_range_end FROM event_categories WHERE id = $1`
+		if err := tx.Get(&rng, q, *categoryID); err != nil {
+			return nil, err
+		}
+	}
+	if !rng.Start.Valid && !rng.End.Valid {
+		if err := tx.Get(&rng, `SELECT bib_range_start, bib_range_end FROM events WHERE id = $1`, eventID); err != nil {
+			return nil, err
+		}
+	}
+	return nextBibInRangeTx(tx, eventID, rng.Start, rng.End)
+}
+
+func nextBibInRangeTx(tx *sqlx.Tx, eventID int, start, end sql.NullInt64) (*int, error) {
+	first := int64(1)
+	if start.Valid {
+		first = start.Int64
+	}
+	last := int64(math.MaxInt32)
+	if end.Valid {
+		last = end.Int64
+	}
+
+	var next int64
+	const q = `
+		SELECT COALESCE(MAX(bib_number), $2 - 1) + 1
+		FROM registrations
+		WHERE event_id = $1 AND bib_number BETWEEN $2 AND $3
+	`
+	if err := tx.Get(&next, q, eventID, first, last); err != nil {
+		return nil, err
+	}
+	if next <= last {
+		bib := int(next)
+		return &bib, nil
+	}
+
+	const gapQ = `
+		SELECT s
+		FROM generate_series($2::int, $3::int) AS s
+		WHERE NOT EXISTS (
+			SELECT 1 FROM registrations WHERE event_id = $1 AND bib_number = s
+		)
+		ORDER BY s
+		LIMIT 1
+	`
+	var gap int
+	if err := tx.Get(&gap, gapQ, eventID, first, last); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // rango agotado
+		}
+		return nil, err
+	}
+	return &gap, nil
+}
+
+// SetRegistrationBib reasigna manualmente el dorsal de una inscripción (nil lo quita)
+func SetRegistrationBib(eventID, registrationID int, bib *int) error {
+	res, err := config.DB.Exec(`UPDATE registrations SET bib_number = $1 WHERE id = $2 AND event_id = $3`, bib, registrationID, eventID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrRegistrationNotFound
+	}
+	return nil
+}
+
+// GetEventBibs lista los dorsales asignados del evento
+func GetEventBibs(eventID int) ([]models.EventRegistrationUser, error) {
+	var rows []models.EventRegistrationUser
+	const q = `
+		SELECT
+			r.id         AS registration_id,
+			u.id         AS user_id,
+			u.name       AS user_name,
+			u.email      AS user_email,
+			r.bib_number AS bib_number,
+			c.id         AS category_id,
+			c.name       AS category_name
+		FROM registrations r
+		JOIN users u ON u.id = r.user_id
+		LEFT JOIN event_categories c ON c.id = r.category_id
+		WHERE r.event_id = $1 AND r.bib_number IS NOT NULL
+		ORDER BY r.bib_number ASC
+	`
+	err := config.DB.Select(&rows, q, eventID)
+	return rows, err
+}
+
+// GetRegistrationByBib busca la inscripción que tiene ese dorsal en el evento
+func GetRegistrationByBib(eventID, bib int) (models.EventRegistrationUser, error) {
+	var row models.EventRegistrationUser
+	const q = `
+		SELECT
+			r.id         AS registration_id,
+			u.id         AS user_id,
+			u.name       AS user_name,
+			u.email      AS user_email,
+			r.bib_number AS bib_number,
+			c.id         AS category_id,
+			c.name       AS category_name
+		FROM registrations r
+		JOIN users u ON u.id = r.user_id
+		LEFT JOIN event_categories c ON c.id = r.category_id
+		WHERE r.event_id = $1 AND r.bib_number = $2
+	`
+	err := config.DB.Get(&row, q, eventID, bib)
+	return row, err
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
+)
+
+var ErrReminderNotFound = errors.New("recordatorio no encontrado")
+
+// GetEventReminders lista los recordatorios del evento (el más anticipado primero)
+func GetEventReminders(eventID int) ([]models.EventReminder, error) {
+	rows := []models.EventReminder{}
+	const q = `SELECT * FROM event_reminders WHERE event_id = $1 ORDER BY offset_minutes DESC`
+	err := config.DB.Select(&rows, q, eventID)
+	return rows, err
+}
+
+// CreateEventReminder: un offset repetido en el mismo evento devuelve el error 23505 de pq
+func CreateEventReminder(r models.EventReminder) (models.EventReminder, error) {
+	var out models.EventReminder
+	const q = `
+		INSERT INTO event_reminders (event_id, offset_minutes, message)
+		VALUES ($1, $2, $3)
+		RETURNING *
+	`
+	err := config.DB.Get(&out, q, r.EventID, r.OffsetMinutes, r.Message)
+	return out, err
+}
+
+func DeleteEventReminder(eventID, reminderID int) error {
+	res, err := config.DB.Exec(`DELETE FROM event_reminders WHERE id = $1 AND event_id = $2`, reminderID, eventID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrReminderNotFound
+	}
+	return nil
+}
+
+// GetDueReminders: recordatorios pendientes cuya hora llegó, de eventos publicados que aún no empezaron
+func GetDueReminders() ([]int, error) {
+	var ids []int
+	const q = `
+		SELECT r.id
+		FROM event_reminders r
+		JOIN events e ON e.id = r.event_id
+		WHERE r.sent_at IS NULL
+		  AND e.status IN ('published', 'registration_open', 'registration_closed')
+		  AND e.date > NOW()
+		  AND e.date - (r.offset_minutes * INTERVAL '1 minute') <= NOW()
+		ORDER BY e.date ASC, r.id ASC
+	`
+	err := config.DB.Select(&ids, q)
+	return ids, err
+}
+
+// SendReminder encola el recordatorio para cada inscrito: siempre en la bandeja y por email
+// si el usuario no lo desactivó. Si otra ejecución ya lo envió, no hace nada.
+func SendReminder(reminderID int) error {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var reminder models.EventReminder
+	const lockQ = `SELECT * FROM event_reminders WHERE id = $1 AND sent_at IS NULL FOR UPDATE SKIP LOCKED`
+	if err := tx.Get(&reminder, lockQ, reminderID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	var recipients []struct {
+		models.ReminderNotice
+		UserID      int    `db:"user_id"`
+		Email       string `db:"email"`
+		Locale      string `db:"locale"`
+		EmailWanted bool   `db:"email_wanted"`
+	}
+	const q = `
+		SELECT e.id AS event_id, e.name AS event_name, e.date, e.location,
+		       u.id AS user_id, u.name AS user_name, u.email, u.locale,
+		       r.bib_number, c.name AS category_name,
+		       COALESCE(p.email_reminders, TRUE) AS email_wanted
+		FROM registrations r
+		JOIN events e ON e.id = r.event_id
+		JOIN users u ON u.id = r.user_id
+		LEFT JOIN event_categories c ON c.id = r.category_id
+		LEFT JOIN notification_preferences p ON p.user_id = u.id
+		WHERE r.event_id = $1
+	`
+	if err := tx.Select(&recipients, q, reminder.EventID); err != nil {
+		return err
+	}
+
+	for _, rc := range recipients {
+		notice := rc.ReminderNotice
+		notice.Message = reminder.Message
+		payload, err := json.Marshal(notice)
+		if err != nil {
+			return err
+		}
+		if err := insertInboxTx(tx, reminder.EventID, rc.UserID, models.NotificationRaceReminder, payload); err != nil {
+			return err
+		}
+		if !rc.EmailWanted {
+			continue
+		}
+		const emailQ = `
+			INSERT INTO notifications (event_id, user_id, channel, kind, recipient, payload, locale)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`
+		if _, err := tx.Exec(emailQ, reminder.EventID, rc.UserID, models.NotificationChannelEmail, models.NotificationRaceReminder, rc.Email, payload, rc.Locale); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE event_reminders SET sent_at = NOW() WHERE id = $1`, reminder.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// rescheduleRemindersTx vuelve a dejar pendientes los recordatorios ya enviados cuya hora,
+// con la nueva fecha del evento, todavía no llegó
+func rescheduleRemindersTx(tx *sqlx.Tx, eventID int, date time.Time) error {
+	const q = `
+		UPDATE event_reminders
+		SET sent_at = NULL
+		WHERE event_id = $1
+		  AND sent_at IS NOT NULL
+		  AND $2::timestamp - (offset_minutes * INTERVAL '1 minute') > NOW()
+	`
+	_, err := tx.Exec(q, eventID, date)
+	return err
+}
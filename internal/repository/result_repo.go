@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"github.com/jmoiron/sqlx"
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
+)
+
+// BeginResultsUpdate abre una transacción y toma un advisory lock por evento,
+// para que dos recálculos concurrentes no se pisen.
+func BeginResultsUpdate(eventID int) (*sqlx.Tx, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('results'), $1)`, eventID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return tx, nil
+}
+
+// ReplaceEventResultsTx reemplaza los resultados del evento por los recién calculados
+func ReplaceEventResultsTx(tx *sqlx.Tx, eventID int, results []models.RunnerResult) error {
+	if _, err := tx.Exec(`DELETE FROM results WHERE event_id = $1`, eventID); err != nil {
+		return err
+	}
+	const q = `
+		INSERT INTO results (event_id, user_id, category_id, status, overall_rank, started_at, finished_at,
+			elapsed_ms, distance_m, pace_s_per_km, splits, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
+	`
+	for _, res := range results {
+		if _, err := tx.Exec(q,
+			eventID, res.UserID, res.CategoryID, res.Status, res.OverallRank, res.StartedAt, res.FinishedAt,
+			res.ElapsedMS, res.DistanceM, res.PaceSPerKm, res.Splits,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetEventResults devuelve la clasificación: primero los que terminaron (por categoría
+// y puesto) y luego los que siguen en carrera. categoryID filtra por una categoría.
+func GetEventResults(eventID int, categoryID *int) ([]models.RunnerResult, error) {
+	var rows []models.RunnerResult
+	const q = `
+		SELECT
+			r.event_id,
+			r.user_id,
+			u.name AS user_name,
+			reg.bib_number,
+			r.category_id,
+			c.name AS category_name,
+			r.status,
+			r.overall_rank,
+			r.started_at,
+			r.finished_at,
+			r.elapsed_ms,
+			r.distance_m,
+			r.pace_s_per_km,
+			r.splits,
+			r.updated_at
+		FROM results r
+		JOIN users u ON u.id = r.user_id
+		LEFT JOIN registrations reg ON reg.event_id = r.event_id AND reg.user_id = r.user_id
+		LEFT JOIN event_categories c ON c.id = r.category_id
+		WHERE r.event_id = $1 AND ($2::int IS NULL OR r.category_id = $2)
+		ORDER BY c.distance_m ASC NULLS LAST, r.category_id ASC NULLS FIRST,
+			r.overall_rank ASC NULLS LAST, r.started_at ASC NULLS LAST, r.user_id ASC
+	`
+	err := config.DB.Select(&rows, q, eventID, categoryID)
+	return rows, err
+}
+
+// GetFinishedResultsWithProfile devuelve los que terminaron junto con género y fecha de
+// nacimiento, para armar las clasificaciones por grupo de edad.
+func GetFinishedResultsWithProfile(eventID int, categoryID *int) ([]models.AgeGroupResult, error) {
+	var rows []models.AgeGroupResult
+	const q = `
+		SELECT
+			r.event_id,
+			r.user_id,
+			u.name AS user_name,
+			u.gender,
+			u.birthdate,
+			reg.bib_number,
+			r.category_id,
+			c.name AS category_name,
+			r.status,
+			r.overall_rank,
+			r.started_at,
+			r.finished_at,
+			r.elapsed_ms,
+			r.distance_m,
+			r.pace_s_per_km,
+			r.splits,
+			r.updated_at
+		FROM results r
+		JOIN users u ON u.id = r.user_id
+		LEFT JOIN registrations reg ON reg.event_id = r.event_id AND reg.user_id = r.user_id
+		LEFT JOIN event_categories c ON c.id = r.category_id
+		WHERE r.event_id = $1 AND r.status = 'finished' AND ($2::int IS NULL OR r.category_id = $2)
+		ORDER BY c.distance_m ASC NULLS LAST, r.category_id ASC NULLS FIRST, r.overall_rank ASC
+	`
+	err := config.DB.Select(&rows, q, eventID, categoryID)
+	return rows, err
+}
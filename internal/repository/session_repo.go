@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
+)
+
+var (
+	ErrSessionNotFound    = errors.New("sesión no encontrada o expirada")
+	ErrRefreshTokenReused = errors.New("refresh token reutilizado; la sesión fue revocada")
+)
+
+func CreateSession(userID int, tokenHash string, expiresAt time.Time, userAgent, ip string) (int, error) {
+	const q = `
+		INSERT INTO sessions (user_id, refresh_token_hash, expires_at, user_agent, ip)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	var id int
+	err := config.DB.Get(&id, q, userID, tokenHash, expiresAt, nullIfEmpty(userAgent), nullIfEmpty(ip))
+	return id, err
+}
+
+// RotateSession cambia el refresh token de una sesión vigente por uno nuevo.
+// Si el token presentado es el anterior de alguna sesión (ya rotado), se asume robado y
+// la sesión se revoca.
+func RotateSession(oldHash, newHash, ip string) (models.Session, error) {
+	var s models.Session
+	const q = `
+		UPDATE sessions
+		SET previous_token_hash = refresh_token_hash,
+		    refresh_token_hash = $2,
+		    last_used_at = NOW(),
+		    ip = COALESCE($3, ip)
+		WHERE refresh_token_hash = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		RETURNING *
+	`
+	err := config.DB.Get(&s, q, oldHash, newHash, nullIfEmpty(ip))
+	if err == nil {
+		return s, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return s, err
+	}
+
+	const reuseQ = `
+		UPDATE sessions
+		SET revoked_at = NOW(), revoked_reason = $2
+		WHERE previous_token_hash = $1 AND revoked_at IS NULL
+	`
+	res, err := config.DB.Exec(reuseQ, oldHash, models.SessionRevokedTokenReuse)
+	if err != nil {
+		return s, err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return s, ErrRefreshTokenReused
+	}
+	return s, ErrSessionNotFound
+}
+
+// GetSessionRole devuelve el rol vigente del usuario si la sesión del access token sigue
+// activa y la cuenta no está suspendida (ok = false en otro caso)
+func GetSessionRole(sessionID, userID int) (role string, ok bool, err error) {
+	const q = `
+		SELECT u.role
+		FROM sessions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.id = $1 AND s.user_id = $2
+		  AND s.revoked_at IS NULL AND s.expires_at > NOW()
+		  AND u.suspended_at IS NULL
+	`
+	err = config.DB.Get(&role, q, sessionID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	return role, err == nil, err
+}
+
+// RevokeSessionByToken revoca la sesión dueña del refresh token (logout)
+func RevokeSessionByToken(tokenHash string) (bool, error) {
+	const q = `
+		UPDATE sessions SET revoked_at = NOW(), revoked_reason = $2
+		WHERE refresh_token_hash = $1 AND revoked_at IS NULL
+	`
+	res, err := config.DB.Exec(q, tokenHash, models.SessionRevokedLogout)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// RevokeSession revoca una sesión del usuario
+func RevokeSession(userID, sessionID int) (bool, error) {
+	const q = `
+		UPDATE sessions SET revoked_at = NOW(), revoked_reason = $3
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+	res, err := config.DB.Exec(q, sessionID, userID, models.SessionRevokedLogout)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// RevokeUserSessions revoca todas las sesiones activas del usuario ("cerrar sesión en todos
+// los dispositivos") y devuelve cuántas eran
+func RevokeUserSessions(userID int, reason string) (int64, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	n, err := revokeUserSessionsTx(tx, userID, reason)
+	if err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
+
+func revokeUserSessionsTx(tx *sqlx.Tx, userID int, reason string) (int64, error) {
+	const q = `
+		UPDATE sessions SET revoked_at = NOW(), revoked_reason = $2
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+	res, err := tx.Exec(q, userID, reason)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetActiveSessions lista las sesiones vigentes del usuario (la más reciente primero)
+func GetActiveSessions(userID int) ([]models.Session, error) {
+	rows := []models.Session{}
+	const q = `
+		SELECT * FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_used_at DESC
+	`
+	err := config.DB.Select(&rows, q, userID)
+	return rows, err
+}
+
+// PurgeSessions borra las sesiones revocadas o vencidas hace más de olderThan
+func PurgeSessions(olderThan time.Duration) (int64, error) {
+	const q = `
+		DELETE FROM sessions
+		WHERE COALESCE(revoked_at, expires_at) < NOW() - ($1 * INTERVAL '1 second')
+	`
+	res, err := config.DB.Exec(q, int64(olderThan.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
+)
+
+// AssignStaff asigna un usuario como staff del evento (checkpointID nil = todos los checkpoints)
+func AssignStaff(eventID, userID int, checkpointID *int, assignedBy int) (int, error) {
+	const q = `
+		INSERT INTO event_staff (event_id, user_id, checkpoint_id, assigned_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	var id int
+	err := config.DB.Get(&id, q, eventID, userID, checkpointID, assignedBy)
+	return id, err
+}
+
+func GetEventStaff(eventID int) ([]models.EventStaff, error) {
+	var rows []models.EventStaff
+	const q = `
+		SELECT
+			s.id,
+			s.event_id,
+			s.user_id,
+			u.name  AS user_name,
+			u.email AS user_email,
+			s.checkpoint_id,
+			s.assigned_by,
+			s.created_at
+		FROM event_staff s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.event_id = $1
+		ORDER BY s.checkpoint_id NULLS FIRST, u.name
+	`
+	err := config.DB.Select(&rows, q, eventID)
+	return rows, err
+}
+
+// RemoveStaff elimina una asignación; retorna (bool) si eliminó algo
+func RemoveStaff(eventID, staffID int) (bool, error) {
+	res, err := config.DB.Exec(`DELETE FROM event_staff WHERE id = $1 AND event_id = $2`, staffID, eventID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// IsUserRegistered indica si el usuario tiene inscripción (no lista de espera) en el evento
+func IsUserRegistered(userID, eventID int) (bool, error) {
+	var ok bool
+	err := config.DB.Get(&ok, `SELECT EXISTS(SELECT 1 FROM registrations WHERE user_id = $1 AND event_id = $2)`, userID, eventID)
+	return ok, err
+}
@@ -23,7 +23,14 @@ func GetUserByEmail(email string) (models.User, error) {
 
 func GetUserByID(id int) (models.User, error) {
 	var user models.User
-	query := `SELECT id, name, email, role, created_at FROM users WHERE id = $1`
+	query := `SELECT id, name, email, role, created_at, birthdate, gender, locale, email_verified_at, suspended_at, suspended_reason FROM users WHERE id = $1`
 	err := config.DB.Get(&user, query, id)
 	return user, err
+}
+
+// UpdateUserProfile actualiza los datos editables del perfil
+func UpdateUserProfile(u models.User) error {
+	query := `UPDATE users SET name = $1, birthdate = $2, gender = $3, locale = $4 WHERE id = $5`
+	_, err := config.DB.Exec(query, u.Name, u.Birthdate, u.Gender, u.Locale, u.ID)
+	return err
 }
\ No newline at end of file
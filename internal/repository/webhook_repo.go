@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"sport-events-backend/internal/config"
+	"sport-events-backend/internal/models"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook no encontrado")
+	ErrWebhookDeliveryNotFound = errors.New("entrega no encontrada")
+)
+
+// enqueueWebhooksTx escribe una entrega por cada endpoint suscrito a eventType (los de ese
+// evento y los de toda la cuenta) cuyo dueño puede editar el evento: owner o co_organizer
+// del equipo, permiso event:edit concedido o, para los endpoints del evento, admin
+// (las mismas reglas que policy.EventEdit). Va en la transacción del cambio.
+func enqueueWebhooksTx(tx *sqlx.Tx, eventID int, eventType string, data interface{}) error {
+	payload, err := json.Marshal(models.WebhookEnvelope{
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		EventID:    eventID,
+		Data:       data,
+	})
+	if err != nil {
+		return err
+	}
+	const q = `
+		INSERT INTO webhook_deliveries (endpoint_id, event_id, event_type, payload)
+		SELECT w.id, e.id, $2, $3
+		FROM events e
+		JOIN webhook_endpoints w ON w.event_id IS NULL OR w.event_id = e.id
+		JOIN users u ON u.id = w.owner_id
+		WHERE e.id = $1
+		  AND (cardinality(w.event_types) = 0 OR $2 = ANY(w.event_types))
+		  AND (
+		    EXISTS (
+		      SELECT 1 FROM event_members m
+		      WHERE m.event_id = e.id AND m.user_id = w.owner_id AND m.role IN ('owner', 'co_organizer')
+		    )
+		    OR EXISTS (
+		      SELECT 1 FROM event_permission_grants g
+		      WHERE g.event_id = e.id AND g.user_id = w.owner_id AND g.permission = 'event:edit'
+		    )
+		    OR (w.event_id = e.id AND u.role = 'admin')
+		  )
+	`
+	_, err = tx.Exec(q, eventID, eventType, payload)
+	return err
+}
+
+// registrationWebhookDataTx arma el payload de una inscripción (antes de borrarla, al cancelar)
+func registrationWebhookDataTx(tx *sqlx.Tx, userID, eventID int) (models.WebhookRegistration, error) {
+	var data models.WebhookRegistration
+	const q = `
+		SELECT r.id AS registration_id, u.id AS user_id, u.name AS user_name,
+		       r.bib_number, r.category_id, c.name AS category_name
+		FROM registrations r
+		JOIN users u ON u.id = r.user_id
+		LEFT JOIN event_categories c ON c.id = r.category_id
+		WHERE r.user_id = $1 AND r.event_id = $2
+	`
+	err := tx.Get(&data, q, userID, eventID)
+	return data, err
+}
+
+func CreateWebhookEndpoint(ep models.WebhookEndpoint) (models.WebhookEndpoint, error) {
+	const q = `
+		INSERT INTO webhook_endpoints (owner_id, event_id, url, secret, event_types)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING *
+	`
+	var out models.WebhookEndpoint
+	err := config.DB.Get(&out, q, ep.OwnerID, ep.EventID, ep.URL, ep.Secret, ep.EventTypes)
+	return out, err
+}
+
+func GetWebhookEndpointsByOwner(ownerID int) ([]models.WebhookEndpoint, error) {
+	rows := []models.WebhookEndpoint{}
+	const q = `SELECT * FROM webhook_endpoints WHERE owner_id = $1 ORDER BY created_at ASC, id ASC`
+	err := config.DB.Select(&rows, q, ownerID)
+	return rows, err
+}
+
+// GetWebhookEndpoint devuelve el endpoint solo si pertenece al organizador
+func GetWebhookEndpoint(id, ownerID int) (models.WebhookEndpoint, error) {
+	var ep models.WebhookEndpoint
+	err := config.DB.Get(&ep, `SELECT * FROM webhook_endpoints WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ep, ErrWebhookNotFound
+	}
+	return ep, err
+}
+
+func DeleteWebhookEndpoint(id, ownerID int) (bool, error) {
+	res, err := config.DB.Exec(`DELETE FROM webhook_endpoints WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// GetWebhookDeliveries devuelve el log de entregas del endpoint (más recientes primero)
+func GetWebhookDeliveries(endpointID, limit int) ([]models.WebhookDelivery, error) {
+	rows := []models.WebhookDelivery{}
+	const q = `
+		SELECT * FROM webhook_deliveries
+		WHERE endpoint_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+	err := config.DB.Select(&rows, q, endpointID, limit)
+	return rows, err
+}
+
+// ReplayWebhookDelivery encola una copia de la entrega con el mismo payload
+func ReplayWebhookDelivery(endpointID, deliveryID int) (models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	const q = `
+		INSERT INTO webhook_deliveries (endpoint_id, event_id, event_type, payload, replay_of)
+		SELECT endpoint_id, event_id, event_type, payload, id
+		FROM webhook_deliveries
+		WHERE id = $1 AND endpoint_id = $2
+		RETURNING *
+	`
+	err := config.DB.Get(&d, q, deliveryID, endpointID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return d, ErrWebhookDeliveryNotFound
+	}
+	return d, err
+}
+
+// ClaimDueWebhookDeliveries toma hasta limit entregas pendientes con un lease, igual que
+// ClaimDueNotifications, junto con la URL y el secreto del endpoint.
+// Las que vencieron el lease sin intentos restantes pasan a "failed" en vez de reintentarse.
+func ClaimDueWebhookDeliveries(limit int, lease time.Duration) ([]models.WebhookDeliveryTarget, error) {
+	tx, err := config.DB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	const exhaustedQ = `
+		UPDATE webhook_deliveries
+		SET status = 'failed',
+		    last_error = COALESCE(last_error, 'lease vencido sin intentos restantes')
+		WHERE status = 'sending' AND next_attempt_at <= NOW() AND attempts >= max_attempts
+	`
+	if _, err := tx.Exec(exhaustedQ); err != nil {
+		return nil, err
+	}
+
+	var rows []models.WebhookDeliveryTarget
+	const q = `
+		UPDATE webhook_deliveries d
+		SET status = 'sending',
+		    attempts = d.attempts + 1,
+		    next_attempt_at = NOW() + ($2 * INTERVAL '1 second')
+		FROM webhook_endpoints w
+		WHERE w.id = d.endpoint_id
+		  AND d.id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status IN ('pending', 'sending') AND next_attempt_at <= NOW()
+			  AND attempts < max_attempts
+			ORDER BY next_attempt_at ASC, id ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		  )
+		RETURNING d.*, w.url, w.secret
+	`
+	if err := tx.Select(&rows, q, limit, int64(lease.Seconds())); err != nil {
+		return nil, err
+	}
+	return rows, tx.Commit()
+}
+
+func MarkWebhookDelivered(id, responseStatus int) error {
+	const q = `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', delivered_at = NOW(), response_status = $2, last_error = NULL
+		WHERE id = $1
+	`
+	_, err := config.DB.Exec(q, id, responseStatus)
+	return err
+}
+
+// MarkWebhookFailed programa un reintento o, si se agotaron, la deja en "failed".
+// responseStatus es 0 si el endpoint no respondió.
+func MarkWebhookFailed(d models.WebhookDelivery, responseStatus int, cause error, retryIn time.Duration) error {
+	const q = `
+		UPDATE webhook_deliveries
+		SET status = CASE WHEN attempts >= max_attempts THEN 'failed' ELSE 'pending' END,
+		    next_attempt_at = NOW() + ($2 * INTERVAL '1 second'),
+		    response_status = NULLIF($3, 0),
+		    last_error = $4
+		WHERE id = $1
+	`
+	_, err := config.DB.Exec(q, d.ID, int64(retryIn.Seconds()), responseStatus, cause.Error())
+	return err
+}
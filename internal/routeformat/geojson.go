@@ -0,0 +1,60 @@
+package routeformat
+
+import (
+	"encoding/json"
+	"io"
+
+	"sport-events-backend/internal/models"
+)
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// EncodeGeoJSON escribe la ruta como FeatureCollection: un LineString con el trazado
+// y un Point por checkpoint. Las coordenadas van en orden [lng, lat(, ele)].
+func EncodeGeoJSON(w io.Writer, name string, route models.Route) error {
+	features := []geoJSONFeature{}
+	if len(route.Polyline) > 0 {
+		coords := make([][]float64, len(route.Polyline))
+		for i, p := range route.Polyline {
+			coords[i] = []float64{p.Lng, p.Lat}
+			if p.Ele != nil {
+				coords[i] = append(coords[i], *p.Ele)
+			}
+		}
+		features = append(features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geoJSONGeometry{Type: "LineString", Coordinates: coords},
+			Properties: map[string]interface{}{"name": name},
+		})
+	}
+	for _, cp := range models.SortCheckpoints(route.Checkpoints) {
+		props := map[string]interface{}{
+			"id":    cp.ID,
+			"name":  cp.Name,
+			"type":  cp.Type,
+			"order": cp.Order,
+		}
+		if cp.RadiusM > 0 {
+			props["radius_m"] = cp.RadiusM
+		}
+		features = append(features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geoJSONGeometry{Type: "Point", Coordinates: []float64{cp.Lng, cp.Lat}},
+			Properties: props,
+		})
+	}
+
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"type":     "FeatureCollection",
+		"features": features,
+	})
+}
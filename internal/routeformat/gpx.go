@@ -0,0 +1,134 @@
+package routeformat
+
+import (
+	"encoding/xml"
+	"io"
+
+	"sport-events-backend/internal/models"
+)
+
+type gpxFile struct {
+	XMLName   xml.Name     `xml:"gpx"`
+	Version   string       `xml:"version,attr"`
+	Creator   string       `xml:"creator,attr"`
+	Xmlns     string       `xml:"xmlns,attr,omitempty"`
+	Metadata  *gpxMetadata `xml:"metadata,omitempty"`
+	Waypoints []gpxPoint   `xml:"wpt"`
+	Routes    []gpxRoute   `xml:"rte"`
+	Tracks    []gpxTrack   `xml:"trk"`
+}
+
+type gpxMetadata struct {
+	Name string `xml:"name,omitempty"`
+	Desc string `xml:"desc,omitempty"`
+}
+
+type gpxPoint struct {
+	Lat        float64        `xml:"lat,attr"`
+	Lon        float64        `xml:"lon,attr"`
+	Ele        *float64       `xml:"ele,omitempty"`
+	Name       string         `xml:"name,omitempty"`
+	Desc       string         `xml:"desc,omitempty"`
+	Type       string         `xml:"type,omitempty"`
+	Extensions *gpxExtensions `xml:"extensions,omitempty"`
+}
+
+// gpxExtensionsNS: espacio de nombres de los datos propios de los waypoints exportados
+const gpxExtensionsNS = "urn:sport-events-backend:gpx:1"
+
+// gpxExtensions conserva el ID y el radio del checkpoint para que reimportar un GPX
+// exportado no renumere los checkpoints (los checkins y el staff apuntan a esos IDs)
+type gpxExtensions struct {
+	ID      int     `xml:"urn:sport-events-backend:gpx:1 id,omitempty"`
+	RadiusM float64 `xml:"urn:sport-events-backend:gpx:1 radius_m,omitempty"`
+}
+
+type gpxRoute struct {
+	Name   string     `xml:"name,omitempty"`
+	Points []gpxPoint `xml:"rtept"`
+}
+
+type gpxTrack struct {
+	Name     string       `xml:"name,omitempty"`
+	Segments []gpxSegment `xml:"trkseg"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+// ParseGPX lee un GPX 1.1: los tracks (o, si no hay, las rutas) forman el trazado
+// y los waypoints se convierten en checkpoints.
+func ParseGPX(r io.Reader) (*models.Route, error) {
+	var f gpxFile
+	if err := xml.NewDecoder(r).Decode(&f); err != nil {
+		return nil, err
+	}
+
+	var polyline []models.RoutePoint
+	for _, trk := range f.Tracks {
+		for _, seg := range trk.Segments {
+			for _, p := range seg.Points {
+				polyline = append(polyline, models.RoutePoint{Lat: p.Lat, Lng: p.Lon, Ele: p.Ele})
+			}
+		}
+	}
+	if len(polyline) == 0 {
+		for _, rte := range f.Routes {
+			for _, p := range rte.Points {
+				polyline = append(polyline, models.RoutePoint{Lat: p.Lat, Lng: p.Lon, Ele: p.Ele})
+			}
+		}
+	}
+
+	wpts := make([]waypoint, 0, len(f.Waypoints))
+	for _, p := range f.Waypoints {
+		wp := waypoint{Name: p.Name, Type: p.Type, Lat: p.Lat, Lng: p.Lon}
+		if p.Extensions != nil {
+			wp.ID, wp.RadiusM = p.Extensions.ID, p.Extensions.RadiusM
+		}
+		wpts = append(wpts, wp)
+	}
+
+	metadata := map[string]interface{}{"source": FormatGPX}
+	if f.Metadata != nil && f.Metadata.Name != "" {
+		metadata["name"] = f.Metadata.Name
+	} else if len(f.Tracks) > 0 && f.Tracks[0].Name != "" {
+		metadata["name"] = f.Tracks[0].Name
+	}
+
+	return buildRoute(polyline, wpts, metadata)
+}
+
+// EncodeGPX escribe la ruta como GPX 1.1 (trazado como track, checkpoints como waypoints)
+func EncodeGPX(w io.Writer, name string, route models.Route) error {
+	f := gpxFile{
+		Version:  "1.1",
+		Creator:  "sport-events-backend",
+		Xmlns:    "http://www.topografix.com/GPX/1/1",
+		Metadata: &gpxMetadata{Name: name},
+	}
+	for _, cp := range models.SortCheckpoints(route.Checkpoints) {
+		f.Waypoints = append(f.Waypoints, gpxPoint{
+			Lat:        cp.Lat,
+			Lon:        cp.Lng,
+			Name:       cp.Name,
+			Type:       cp.Type,
+			Extensions: &gpxExtensions{ID: cp.ID, RadiusM: cp.RadiusM},
+		})
+	}
+	if len(route.Polyline) > 0 {
+		seg := gpxSegment{}
+		for _, p := range route.Polyline {
+			seg.Points = append(seg.Points, gpxPoint{Lat: p.Lat, Lon: p.Lng, Ele: p.Ele})
+		}
+		f.Tracks = []gpxTrack{{Name: name, Segments: []gpxSegment{seg}}}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(f)
+}
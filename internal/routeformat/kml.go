@@ -0,0 +1,220 @@
+package routeformat
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"sport-events-backend/internal/models"
+)
+
+type kmlPlacemark struct {
+	Name         string          `xml:"name"`
+	Description  string          `xml:"description"`
+	Point        *kmlGeometry    `xml:"Point"`
+	LineString   *kmlGeometry    `xml:"LineString"`
+	MultiGeom    *kmlMultiGeom   `xml:"MultiGeometry"`
+	ExtendedData *kmlExtendedDat `xml:"ExtendedData"`
+}
+
+type kmlGeometry struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlMultiGeom struct {
+	LineStrings []kmlGeometry `xml:"LineString"`
+	Points      []kmlGeometry `xml:"Point"`
+}
+
+type kmlExtendedDat struct {
+	Data []kmlData `xml:"Data"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+func (p kmlPlacemark) data(name string) string {
+	if p.ExtendedData == nil {
+		return ""
+	}
+	for _, d := range p.ExtendedData.Data {
+		if d.Name == name {
+			return d.Value
+		}
+	}
+	return ""
+}
+
+// ParseKML lee un KML 2.2: los LineString forman el trazado y los Point se convierten
+// en checkpoints. Los Placemark pueden estar anidados en Document/Folder.
+func ParseKML(r io.Reader) (*models.Route, error) {
+	dec := xml.NewDecoder(r)
+	var (
+		polyline []models.RoutePoint
+		wpts     []waypoint
+		docName  string
+		depth    int
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch se := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch se.Name.Local {
+			case "Placemark":
+				var pm kmlPlacemark
+				if err := dec.DecodeElement(&pm, &se); err != nil {
+					return nil, err
+				}
+				depth--
+
+				var lines []kmlGeometry
+				var points []kmlGeometry
+				if pm.LineString != nil {
+					lines = append(lines, *pm.LineString)
+				}
+				if pm.Point != nil {
+					points = append(points, *pm.Point)
+				}
+				if pm.MultiGeom != nil {
+					lines = append(lines, pm.MultiGeom.LineStrings...)
+					points = append(points, pm.MultiGeom.Points...)
+				}
+
+				for _, ls := range lines {
+					pts, err := parseKMLCoordinates(ls.Coordinates)
+					if err != nil {
+						return nil, err
+					}
+					polyline = append(polyline, pts...)
+				}
+				for _, pt := range points {
+					pts, err := parseKMLCoordinates(pt.Coordinates)
+					if err != nil {
+						return nil, err
+					}
+					if len(pts) == 0 {
+						continue
+					}
+					// id y radius_m están presentes en los KML exportados por esta API
+					id, _ := strconv.Atoi(pm.data("id"))
+					radius, _ := strconv.ParseFloat(pm.data("radius_m"), 64)
+					wpts = append(wpts, waypoint{ID: id, Name: pm.Name, Type: pm.data("type"), Lat: pts[0].Lat, Lng: pts[0].Lng, RadiusM: radius})
+				}
+			case "name":
+				// nombre del Document (primer <name> fuera de un Placemark)
+				if docName == "" && depth <= 3 {
+					var name string
+					if err := dec.DecodeElement(&name, &se); err != nil {
+						return nil, err
+					}
+					depth--
+					docName = strings.TrimSpace(name)
+				}
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	metadata := map[string]interface{}{"source": FormatKML}
+	if docName != "" {
+		metadata["name"] = docName
+	}
+	return buildRoute(polyline, wpts, metadata)
+}
+
+// parseKMLCoordinates interpreta "lng,lat[,ele] lng,lat[,ele] ..."
+func parseKMLCoordinates(s string) ([]models.RoutePoint, error) {
+	var pts []models.RoutePoint
+	for _, tuple := range strings.Fields(s) {
+		parts := strings.Split(tuple, ",")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("coordenada KML inválida: %q", tuple)
+		}
+		lng, err1 := strconv.ParseFloat(parts[0], 64)
+		lat, err2 := strconv.ParseFloat(parts[1], 64)
+		if err := errors.Join(err1, err2); err != nil {
+			return nil, fmt.Errorf("coordenada KML inválida: %q", tuple)
+		}
+		p := models.RoutePoint{Lat: lat, Lng: lng}
+		if len(parts) > 2 {
+			if ele, err := strconv.ParseFloat(parts[2], 64); err == nil {
+				p.Ele = &ele
+			}
+		}
+		pts = append(pts, p)
+	}
+	return pts, nil
+}
+
+// EncodeKML escribe la ruta como KML 2.2 (trazado como LineString, checkpoints como Point)
+func EncodeKML(w io.Writer, name string, route models.Route) error {
+	type kmlOutPlacemark struct {
+		Name         string          `xml:"name"`
+		ExtendedData *kmlExtendedDat `xml:"ExtendedData,omitempty"`
+		Point        *kmlGeometry    `xml:"Point,omitempty"`
+		LineString   *kmlGeometry    `xml:"LineString,omitempty"`
+	}
+	type kmlDocument struct {
+		Name       string            `xml:"name"`
+		Placemarks []kmlOutPlacemark `xml:"Placemark"`
+	}
+	type kmlFile struct {
+		XMLName  xml.Name    `xml:"kml"`
+		Xmlns    string      `xml:"xmlns,attr"`
+		Document kmlDocument `xml:"Document"`
+	}
+
+	f := kmlFile{Xmlns: "http://www.opengis.net/kml/2.2", Document: kmlDocument{Name: name}}
+	if len(route.Polyline) > 0 {
+		coords := make([]string, len(route.Polyline))
+		for i, p := range route.Polyline {
+			coords[i] = formatKMLCoordinate(p)
+		}
+		f.Document.Placemarks = append(f.Document.Placemarks, kmlOutPlacemark{
+			Name:       name,
+			LineString: &kmlGeometry{Coordinates: strings.Join(coords, " ")},
+		})
+	}
+	for _, cp := range models.SortCheckpoints(route.Checkpoints) {
+		data := []kmlData{
+			{Name: "id", Value: strconv.Itoa(cp.ID)},
+			{Name: "type", Value: cp.Type},
+		}
+		if cp.RadiusM > 0 {
+			data = append(data, kmlData{Name: "radius_m", Value: strconv.FormatFloat(cp.RadiusM, 'f', -1, 64)})
+		}
+		f.Document.Placemarks = append(f.Document.Placemarks, kmlOutPlacemark{
+			Name:         cp.Name,
+			ExtendedData: &kmlExtendedDat{Data: data},
+			Point:        &kmlGeometry{Coordinates: formatKMLCoordinate(models.RoutePoint{Lat: cp.Lat, Lng: cp.Lng})},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(f)
+}
+
+func formatKMLCoordinate(p models.RoutePoint) string {
+	s := strconv.FormatFloat(p.Lng, 'f', -1, 64) + "," + strconv.FormatFloat(p.Lat, 'f', -1, 64)
+	if p.Ele != nil {
+		s += "," + strconv.FormatFloat(*p.Ele, 'f', -1, 64)
+	}
+	return s
+}
@@ -0,0 +1,128 @@
+// Package routeformat convierte rutas entre models.Route y formatos de archivo
+// usados por relojes y apps de entrenamiento (GPX 1.1, KML 2.2 y GeoJSON).
+package routeformat
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"sport-events-backend/internal/models"
+)
+
+const (
+	FormatGPX     = "gpx"
+	FormatKML     = "kml"
+	FormatGeoJSON = "geojson"
+)
+
+var (
+	ErrUnknownFormat = errors.New("formato de ruta no reconocido (se acepta GPX o KML)")
+	ErrEmptyRoute    = errors.New("el archivo no contiene trazado ni waypoints")
+)
+
+// Detect identifica el formato por el elemento raíz del XML
+func Detect(data []byte) (string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", ErrUnknownFormat
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			switch strings.ToLower(se.Name.Local) {
+			case "gpx":
+				return FormatGPX, nil
+			case "kml":
+				return FormatKML, nil
+			}
+			return "", ErrUnknownFormat
+		}
+	}
+}
+
+// Parse convierte un archivo GPX o KML en una ruta
+func Parse(format string, r io.Reader) (*models.Route, error) {
+	switch format {
+	case FormatGPX:
+		return ParseGPX(r)
+	case FormatKML:
+		return ParseKML(r)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// waypoint es el punto intermedio común a GPX y KML antes de convertirlo en checkpoint
+type waypoint struct {
+	ID      int // opcional; si es 0 se numera por orden de aparición
+	Name    string
+	Type    string
+	Lat     float64
+	Lng     float64
+	RadiusM float64 // opcional; 0 = el radio del evento
+}
+
+// buildRoute arma la ruta: los waypoints pasan a ser checkpoints en orden de aparición.
+// Si el waypoint no declara tipo, el primero es la salida y el último la meta.
+// Si no hay waypoints se crean salida y meta en los extremos del trazado.
+func buildRoute(polyline []models.RoutePoint, wpts []waypoint, metadata map[string]interface{}) (*models.Route, error) {
+	if len(polyline) == 0 && len(wpts) == 0 {
+		return nil, ErrEmptyRoute
+	}
+
+	if len(wpts) == 0 {
+		first, last := polyline[0], polyline[len(polyline)-1]
+		wpts = []waypoint{
+			{Name: "Salida", Type: models.CheckpointTypeStart, Lat: first.Lat, Lng: first.Lng},
+			{Name: "Meta", Type: models.CheckpointTypeFinish, Lat: last.Lat, Lng: last.Lng},
+		}
+	}
+
+	route := &models.Route{Polyline: polyline, Metadata: metadata}
+	for i, wp := range wpts {
+		typ := normalizeType(wp.Type)
+		if typ == "" {
+			switch i {
+			case 0:
+				typ = models.CheckpointTypeStart
+			case len(wpts) - 1:
+				typ = models.CheckpointTypeFinish
+			default:
+				typ = models.CheckpointTypeIntermediate
+			}
+		}
+		name := strings.TrimSpace(wp.Name)
+		if name == "" {
+			name = "Checkpoint " + strconv.Itoa(i+1)
+		}
+		id := wp.ID
+		if id <= 0 {
+			id = i + 1
+		}
+		route.Checkpoints = append(route.Checkpoints, models.Checkpoint{
+			ID:      id,
+			Name:    name,
+			Lat:     wp.Lat,
+			Lng:     wp.Lng,
+			Type:    typ,
+			Order:   i + 1,
+			RadiusM: wp.RadiusM,
+		})
+	}
+	return route, nil
+}
+
+func normalizeType(t string) string {
+	switch strings.ToLower(strings.TrimSpace(t)) {
+	case models.CheckpointTypeStart:
+		return models.CheckpointTypeStart
+	case models.CheckpointTypeFinish:
+		return models.CheckpointTypeFinish
+	case models.CheckpointTypeIntermediate:
+		return models.CheckpointTypeIntermediate
+	}
+	return ""
+}
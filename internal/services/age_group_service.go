@@ -0,0 +1,71 @@
+package services
+
+import (
+	"time"
+
+	"sport-events-backend/internal/models"
+)
+
+// Etiquetas para corredores sin datos suficientes en el perfil
+const (
+	unknownGender   = "unknown"
+	unknownAgeGroup = "unknown"
+)
+
+// AgeGroupRankings agrupa a los que terminaron por categoría, género y grupo de edad
+// (edad cumplida el día de la carrera) y numera el puesto dentro de cada grupo.
+// Los resultados deben venir ordenados por categoría y puesto. top > 0 limita cada podio.
+func AgeGroupRankings(raceDay time.Time, groups models.AgeGroups, results []models.AgeGroupResult, top int) []models.AgeGroupRanking {
+	if len(groups) == 0 {
+		groups = models.DefaultAgeGroups
+	}
+	groups = groups.Normalize()
+
+	type key struct {
+		category int
+		gender   string
+		ageGroup string
+	}
+	index := map[key]int{}
+	rankings := []models.AgeGroupRanking{}
+
+	for _, res := range results {
+		gender := unknownGender
+		if res.Gender != nil {
+			gender = *res.Gender
+		}
+		ageGroup := unknownAgeGroup
+		if res.Birthdate != nil {
+			age := models.AgeOn(*res.Birthdate, raceDay)
+			res.Age = &age
+			if grp, ok := groups.Find(age); ok {
+				ageGroup = grp.Name
+			}
+		}
+
+		k := key{gender: gender, ageGroup: ageGroup}
+		if res.CategoryID != nil {
+			k.category = *res.CategoryID
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(rankings)
+			index[k] = i
+			rankings = append(rankings, models.AgeGroupRanking{
+				CategoryID:   res.CategoryID,
+				CategoryName: res.Category,
+				Gender:       gender,
+				AgeGroup:     ageGroup,
+				Results:      []models.AgeGroupResult{},
+			})
+		}
+
+		ranking := &rankings[i]
+		if top > 0 && len(ranking.Results) >= top {
+			continue
+		}
+		res.GroupRank = len(ranking.Results) + 1
+		ranking.Results = append(ranking.Results, res)
+	}
+	return rankings
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"sport-events-backend/internal/models"
+)
+
+var (
+	ErrCheckpointAlreadyVisited = errors.New("checkpoint ya registrado")
+	ErrCheckpointOutOfSequence  = errors.New("checkpoint fuera de orden")
+)
+
+// ValidateCheckinSequence verifica que el corredor pueda registrar el checkpoint `target`
+// dado lo que ya registró (`visited`).
+//   - strict: debe ser exactamente el siguiente checkpoint pendiente del recorrido
+//   - lenient: puede saltar checkpoints, pero no retroceder
+//
+// En ambos modos un checkpoint no se puede repetir.
+func ValidateCheckinSequence(checkpoints []models.Checkpoint, visited []int, target int, mode string) error {
+	course := models.SortCheckpoints(checkpoints)
+
+	seen := make(map[int]bool, len(visited))
+	for _, id := range visited {
+		seen[id] = true
+	}
+	if seen[target] {
+		return ErrCheckpointAlreadyVisited
+	}
+
+	targetPos, furthest := -1, -1
+	for i, cp := range course {
+		if cp.ID == target {
+			targetPos = i
+		}
+		if seen[cp.ID] {
+			furthest = i
+		}
+	}
+	if targetPos == -1 {
+		return fmt.Errorf("%w: checkpoint %d no pertenece a la ruta", ErrCheckpointOutOfSequence, target)
+	}
+
+	if mode == models.CheckinModeLenient {
+		if targetPos < furthest {
+			return fmt.Errorf("%w: ya pasaste por %q, no puedes retroceder a %q",
+				ErrCheckpointOutOfSequence, course[furthest].Name, course[targetPos].Name)
+		}
+		return nil
+	}
+
+	// strict: el siguiente es el primer checkpoint no visitado
+	for _, cp := range course {
+		if seen[cp.ID] {
+			continue
+		}
+		if cp.ID != target {
+			return fmt.Errorf("%w: el siguiente checkpoint es %q", ErrCheckpointOutOfSequence, cp.Name)
+		}
+		break
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"sport-events-backend/internal/auth"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+)
+
+const (
+	emailVerificationTTL = 48 * time.Hour
+	// Reenvío: como mucho uno por minuto y cinco por día
+	verificationResendInterval = time.Minute
+	verificationResendsPerDay  = 5
+)
+
+var (
+	ErrEmailAlreadyVerified  = errors.New("el email ya está verificado")
+	ErrVerificationThrottled = errors.New("ya se envió un enlace hace poco; inténtalo más tarde")
+)
+
+// SendEmailVerification genera el token y envía el enlace de verificación
+func SendEmailVerification(user models.User) error {
+	token, err := auth.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+	if err := repository.CreateEmailVerificationToken(user.ID, auth.HashToken(token), time.Now().Add(emailVerificationTTL)); err != nil {
+		return err
+	}
+	sendTokenEmail(user.ID, user.Email, "email_verification", user.Locale, map[string]interface{}{
+		"user_name":     user.Name,
+		"verify_url":    tokenURL("EMAIL_VERIFICATION_URL", "http://localhost:3000/verify-email", token),
+		"expires_hours": int(emailVerificationTTL.Hours()),
+	})
+	return nil
+}
+
+// ResendEmailVerification reenvía el enlace respetando el límite de reenvíos
+func ResendEmailVerification(userID int) error {
+	user, err := repository.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	total, last, err := repository.GetVerificationRequests(userID, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if total >= verificationResendsPerDay || (last != nil && time.Since(*last) < verificationResendInterval) {
+		return ErrVerificationThrottled
+	}
+	return SendEmailVerification(user)
+}
+
+// VerifyEmail consume el token del enlace
+func VerifyEmail(token string) error {
+	_, err := repository.VerifyEmailWithToken(auth.HashToken(token))
+	return err
+}
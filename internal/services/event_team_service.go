@@ -0,0 +1,71 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"sport-events-backend/internal/auth"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+)
+
+const eventInvitationTTL = 7 * 24 * time.Hour
+
+var (
+	ErrInvalidInviteEmail = errors.New("email inválido")
+	ErrInvalidMemberRole  = errors.New("rol de equipo inválido (co_organizer, viewer o staff)")
+)
+
+// InviteEventMember crea la invitación y envía el enlace al email invitado.
+// El invitado puede no tener cuenta todavía: la acepta después de registrarse con ese email.
+func InviteEventMember(eventID, inviterID int, email, role string) (int, error) {
+	email = strings.TrimSpace(email)
+	if _, err := mail.ParseAddress(email); err != nil {
+		return 0, ErrInvalidInviteEmail
+	}
+	if !models.InvitableMemberRole(role) {
+		return 0, ErrInvalidMemberRole
+	}
+
+	evt, err := repository.GetEventByID(eventID)
+	if err != nil {
+		return 0, err
+	}
+	inviter, err := repository.GetUserByID(inviterID)
+	if err != nil {
+		return 0, err
+	}
+
+	token, err := auth.NewOpaqueToken()
+	if err != nil {
+		return 0, err
+	}
+	id, err := repository.CreateEventInvitation(eventID, email, role, auth.HashToken(token), inviterID, time.Now().Add(eventInvitationTTL))
+	if err != nil {
+		return 0, err
+	}
+
+	// Con cuenta existente se usa su idioma; si no, el del organizador que invita
+	inviteeID, locale := 0, inviter.Locale
+	if invitee, err := repository.GetUserByEmail(email); err == nil {
+		inviteeID, locale = invitee.ID, invitee.Locale
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+	sendTokenEmail(inviteeID, email, "event_invitation", locale, map[string]interface{}{
+		"event_name":   evt.Name,
+		"inviter_name": inviter.Name,
+		"role":         role,
+		"accept_url":   tokenURL("EVENT_INVITATION_URL", "http://localhost:3000/invitations/accept", token),
+		"expires_days": int(eventInvitationTTL.Hours() / 24),
+	})
+	return id, nil
+}
+
+// AcceptEventInvitation consume el token de la invitación para el usuario autenticado
+func AcceptEventInvitation(token string, userID int) (models.EventInvitation, error) {
+	return repository.AcceptEventInvitation(auth.HashToken(token), userID)
+}
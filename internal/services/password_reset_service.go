@@ -0,0 +1,88 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"sport-events-backend/internal/auth"
+	"sport-events-backend/internal/repository"
+)
+
+const (
+	// passwordResetsPerHour: solicitudes por email antes de ignorar las siguientes
+	passwordResetsPerHour = 3
+	minPasswordLength     = 8
+)
+
+var ErrWeakPassword = errors.New("la contraseña debe tener al menos 8 caracteres")
+
+// RequestPasswordReset genera un token de un solo uso y envía el enlace por email.
+// No informa si el email existe o si se alcanzó el límite: el handler responde igual siempre.
+func RequestPasswordReset(email, ip string) error {
+	user, err := repository.GetUserByEmail(strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	recent, err := repository.CountRecentPasswordResets(user.ID, time.Now().Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if recent >= passwordResetsPerHour {
+		log.Printf("⚠️ Límite de restablecimientos alcanzado para el usuario %d", user.ID)
+		return nil
+	}
+
+	token, err := auth.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+	ttl := passwordResetTTL()
+	if err := repository.CreatePasswordResetToken(user.ID, auth.HashToken(token), time.Now().Add(ttl), ip); err != nil {
+		return err
+	}
+
+	sendTokenEmail(user.ID, user.Email, "password_reset", user.Locale, map[string]interface{}{
+		"user_name":       user.Name,
+		"reset_url":       passwordResetURL(token),
+		"expires_minutes": int(ttl.Minutes()),
+	})
+	return nil
+}
+
+// ResetPassword cambia la contraseña con un token vigente y cierra todas las sesiones
+// del usuario.
+func ResetPassword(token, password string) error {
+	if len(password) < minPasswordLength {
+		return ErrWeakPassword
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	_, err = repository.ResetPasswordWithToken(auth.HashToken(token), string(hashed))
+	return err
+}
+
+// passwordResetTTL: PASSWORD_RESET_TTL_MINUTES (por defecto 60)
+func passwordResetTTL() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_TTL_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 60
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// passwordResetURL arma el enlace del frontend: PASSWORD_RESET_URL?token=...
+func passwordResetURL(token string) string {
+	return tokenURL("PASSWORD_RESET_URL", "http://localhost:3000/reset-password", token)
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"sport-events-backend/internal/geo"
+	"sport-events-backend/internal/models"
+	"sport-events-backend/internal/repository"
+)
+
+// RecomputeEventResults recalcula y guarda los resultados de un evento a partir de sus checkins.
+// Si el evento tiene categorías, cada una se clasifica por separado con su propia ruta.
+func RecomputeEventResults(eventID int) error {
+	route, err := repository.GetEventRoute(eventID)
+	if err != nil {
+		return err
+	}
+	categories, err := repository.GetEventCategories(eventID)
+	if err != nil {
+		return err
+	}
+	var runnerCategory map[int]int
+	if len(categories) > 0 {
+		if runnerCategory, err = repository.GetRegistrationCategories(eventID); err != nil {
+			return err
+		}
+	}
+
+	tx, err := repository.BeginResultsUpdate(eventID)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	checkins, err := repository.GetCheckinsByEventTx(tx, eventID)
+	if err != nil {
+		return err
+	}
+
+	// Agrupar checkins por categoría del corredor (0 = sin categoría → ruta del evento)
+	byCategory := map[int][]repository.Checkin{}
+	for _, c := range checkins {
+		catID := runnerCategory[c.UserID]
+		byCategory[catID] = append(byCategory[catID], c)
+	}
+
+	results := ComputeResults(eventID, routeCheckpoints(route), byCategory[0])
+	for _, cat := range categories {
+		catRoute := cat.Route
+		if catRoute == nil {
+			catRoute = route
+		}
+		catID := cat.ID
+		catResults := ComputeResults(eventID, routeCheckpoints(catRoute), byCategory[cat.ID])
+		for i := range catResults {
+			catResults[i].CategoryID = &catID
+		}
+		results = append(results, catResults...)
+	}
+
+	if err := repository.ReplaceEventResultsTx(tx, eventID, results); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ComputeResults convierte los checkins en resultados. Los checkpoints "start" y "finish"
+// actúan como alfombras de cronometraje; los intermedios generan splits.
+// Se usa el primer paso de cada corredor por cada checkpoint.
+func ComputeResults(eventID int, checkpoints []models.Checkpoint, checkins []repository.Checkin) []models.RunnerResult {
+	checkpoints = models.SortCheckpoints(checkpoints)
+
+	var startID, finishID int
+	hasStart, hasFinish := false, false
+	for _, cp := range checkpoints {
+		switch cp.Type {
+		case models.CheckpointTypeStart:
+			startID, hasStart = cp.ID, true
+		case models.CheckpointTypeFinish:
+			finishID, hasFinish = cp.ID, true
+		}
+	}
+
+	// Distancia acumulada de cada checkpoint: la calculada sobre el trazado al guardar
+	// la ruta o, si no existe, la línea recta entre checkpoints en orden de recorrido
+	cumulative := make(map[int]float64, len(checkpoints))
+	for i, cp := range checkpoints {
+		switch {
+		case cp.DistanceM != nil:
+			cumulative[cp.ID] = *cp.DistanceM
+		case i == 0:
+			cumulative[cp.ID] = 0
+		default:
+			prev := checkpoints[i-1]
+			cumulative[cp.ID] = cumulative[prev.ID] + geo.Haversine(prev.Lat, prev.Lng, cp.Lat, cp.Lng)
+		}
+	}
+
+	// Primer paso de cada corredor por cada checkpoint
+	passes := map[int]map[int]time.Time{}
+	var userIDs []int
+	for _, c := range checkins {
+		byCP, ok := passes[c.UserID]
+		if !ok {
+			byCP = map[int]time.Time{}
+			passes[c.UserID] = byCP
+			userIDs = append(userIDs, c.UserID)
+		}
+		if t, seen := byCP[c.CheckpointID]; !seen || c.CreatedAt.Before(t) {
+			byCP[c.CheckpointID] = c.CreatedAt
+		}
+	}
+	sort.Ints(userIDs)
+
+	results := make([]models.RunnerResult, 0, len(userIDs))
+	for _, userID := range userIDs {
+		byCP := passes[userID]
+		res := models.RunnerResult{EventID: eventID, UserID: userID, Splits: models.Splits{}}
+
+		start, started := byCP[startID]
+		finish, finished := byCP[finishID]
+		started = started && hasStart
+		finished = finished && hasFinish
+
+		if !started {
+			if finished {
+				res.Status = models.ResultStatusIncomplete
+				res.FinishedAt = timePtr(finish)
+			} else {
+				res.Status = models.ResultStatusRunning
+			}
+			results = append(results, res)
+			continue
+		}
+		res.StartedAt = timePtr(start)
+
+		prevAt, prevDist := start, cumulative[startID]
+		for _, cp := range checkpoints {
+			if cp.ID == startID {
+				continue
+			}
+			at, ok := byCP[cp.ID]
+			if !ok || at.Before(start) {
+				continue
+			}
+			dist := cumulative[cp.ID] - cumulative[startID]
+			split := models.Split{
+				CheckpointID:   cp.ID,
+				CheckpointName: cp.Name,
+				At:             at,
+				ElapsedMS:      at.Sub(start).Milliseconds(),
+				SegmentMS:      at.Sub(prevAt).Milliseconds(),
+				DistanceM:      dist,
+				SegmentPace:    pace(at.Sub(prevAt), cumulative[cp.ID]-prevDist),
+			}
+			res.Splits = append(res.Splits, split)
+			prevAt, prevDist = at, cumulative[cp.ID]
+		}
+
+		if finished && !finish.Before(start) {
+			elapsed := finish.Sub(start)
+			dist := cumulative[finishID] - cumulative[startID]
+			res.Status = models.ResultStatusFinished
+			res.FinishedAt = timePtr(finish)
+			res.ElapsedMS = int64Ptr(elapsed.Milliseconds())
+			res.DistanceM = float64Ptr(dist)
+			res.PaceSPerKm = pace(elapsed, dist)
+		} else {
+			res.Status = models.ResultStatusRunning
+		}
+		results = append(results, res)
+	}
+
+	rankResults(results)
+	return results
+}
+
+func routeCheckpoints(route *models.Route) []models.Checkpoint {
+	if route == nil {
+		return nil
+	}
+	return route.Checkpoints
+}
+
+// rankResults asigna la posición general a quienes terminaron (menor tiempo primero)
+func rankResults(results []models.RunnerResult) {
+	var finished []*models.RunnerResult
+	for i := range results {
+		if results[i].Status == models.ResultStatusFinished {
+			finished = append(finished, &results[i])
+		}
+	}
+	sort.SliceStable(finished, func(i, j int) bool {
+		if *finished[i].ElapsedMS != *finished[j].ElapsedMS {
+			return *finished[i].ElapsedMS < *finished[j].ElapsedMS
+		}
+		return finished[i].FinishedAt.Before(*finished[j].FinishedAt)
+	})
+	for i, res := range finished {
+		rank := i + 1
+		res.OverallRank = &rank
+	}
+}
+
+// pace devuelve segundos por kilómetro, o nil si no hay distancia
+func pace(d time.Duration, meters float64) *float64 {
+	if meters <= 0 {
+		return nil
+	}
+	p := d.Seconds() / (meters / 1000)
+	return &p
+}
+
+func timePtr(t time.Time) *time.Time { return &t }
+func int64Ptr(v int64) *int64        { return &v }
+func float64Ptr(v float64) *float64  { return &v }
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"sport-events-backend/internal/geo"
+	"sport-events-backend/internal/models"
+)
+
+// ApplyRouteMetrics calcula la distancia total, el desnivel y la distancia de cada
+// checkpoint desde la salida. Las distancias de los checkpoints se escriben en la ruta.
+// Sin trazado, la distancia se aproxima uniendo los checkpoints en línea recta.
+func ApplyRouteMetrics(route *models.Route) models.RouteMetrics {
+	var m models.RouteMetrics
+	if route == nil {
+		return m
+	}
+
+	course := models.SortCheckpoints(route.Checkpoints)
+	offsets := make(map[int]float64, len(course))
+
+	if len(route.Polyline) >= 2 {
+		pts := make([]geo.Point, len(route.Polyline))
+		for i, p := range route.Polyline {
+			pts[i] = geo.Point{Lat: p.Lat, Lng: p.Lng}
+		}
+		cum := geo.CumulativeDistances(pts)
+		total := cum[len(cum)-1]
+		m.DistanceM = &total
+
+		along := 0.0
+		for _, cp := range course {
+			along = geo.DistanceAlong(pts, cum, geo.Point{Lat: cp.Lat, Lng: cp.Lng}, along)
+			offsets[cp.ID] = along
+		}
+
+		m.ElevationGainM, m.ElevationLossM = elevationGainLoss(route.Polyline)
+	} else if len(course) > 0 {
+		total := 0.0
+		for i, cp := range course {
+			if i > 0 {
+				prev := course[i-1]
+				total += geo.Haversine(prev.Lat, prev.Lng, cp.Lat, cp.Lng)
+			}
+			offsets[cp.ID] = total
+		}
+		m.DistanceM = &total
+	}
+
+	for i := range route.Checkpoints {
+		if d, ok := offsets[route.Checkpoints[i].ID]; ok {
+			route.Checkpoints[i].DistanceM = float64Ptr(d)
+		}
+	}
+	return m
+}
+
+// elevationGainLoss suma ascenso y descenso entre puntos consecutivos con elevación.
+// Devuelve nil si el trazado no trae elevación.
+func elevationGainLoss(polyline []models.RoutePoint) (*float64, *float64) {
+	var gain, loss float64
+	var prev *float64
+	found := false
+	for _, p := range polyline {
+		if p.Ele == nil {
+			continue
+		}
+		if prev != nil {
+			found = true
+			if diff := *p.Ele - *prev; diff > 0 {
+				gain += diff
+			} else {
+				loss -= diff
+			}
+		}
+		prev = p.Ele
+	}
+	if !found {
+		return nil, nil
+	}
+	return &gain, &loss
+}
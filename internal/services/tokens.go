@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"sport-events-backend/internal/mailer"
+)
+
+// tokenURL arma el enlace del frontend (<env o def>?token=...)
+func tokenURL(env, def, token string) string {
+	base := os.Getenv(env)
+	if base == "" {
+		base = def
+	}
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	return base + sep + "token=" + url.QueryEscape(token)
+}
+
+// sendTokenEmail envía en segundo plano un email que lleva un token en claro. No pasa por
+// el outbox para no guardar el token, y tampoco retrasa la respuesta de la request.
+func sendTokenEmail(userID int, to, template, locale string, data map[string]interface{}) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := mailer.Default().SendTemplate(ctx, to, template, locale, data); err != nil {
+			log.Printf("⚠️ Error enviando %s al usuario %d: %v", template, userID, err)
+		}
+	}()
+}
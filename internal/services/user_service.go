@@ -2,27 +2,43 @@ package services
 
 import (
 	"errors"
+	"log"
 
 	"golang.org/x/crypto/bcrypt"
 	"sport-events-backend/internal/models"
 	"sport-events-backend/internal/repository"
 )
 
-func RegisterUser(name, email, password, role string) error {
+// RegisterUser crea la cuenta siempre como runner; para organizar eventos se pide
+// el rol con una solicitud que revisa un admin.
+func RegisterUser(name, email, password string) (models.User, error) {
 	// encriptar password
 	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return err
+		return models.User{}, err
 	}
 
 	user := models.User{
 		Name:     name,
 		Email:    email,
 		Password: string(hashed),
-		Role:     role,
+		Role:     models.RoleRunner,
 	}
 
-	return repository.CreateUser(user)
+	if err := repository.CreateUser(user); err != nil {
+		return models.User{}, err
+	}
+
+	// La cuenta queda pendiente de verificar; un fallo al enviar el enlace no impide el
+	// registro (el usuario puede pedir otro)
+	created, err := repository.GetUserByEmail(email)
+	if err != nil {
+		return models.User{}, err
+	}
+	if err := SendEmailVerification(created); err != nil {
+		log.Printf("⚠️ Error generando la verificación de email del usuario %d: %v", created.ID, err)
+	}
+	return created, nil
 }
 
 func AuthenticateUser(email, password string) (models.User, error) {
@@ -35,6 +51,9 @@ func AuthenticateUser(email, password string) (models.User, error) {
 	if err != nil {
 		return models.User{}, errors.New("contraseña inválida")
 	}
+	if user.SuspendedAt != nil {
+		return models.User{}, repository.ErrUserSuspended
+	}
 
 	return user, nil
 }
@@ -0,0 +1,182 @@
+// Package webhooks firma y entrega los webhooks de los organizadores.
+//
+// Cada entrega es un POST con el sobre JSON y estas cabeceras:
+//
+//	X-Webhook-Event:     tipo de evento (registration.created, checkin.recorded, ...)
+//	X-Webhook-Delivery:  ID de la entrega (se repite en los reintentos)
+//	X-Webhook-Signature: t=<unix>,v1=<hex(HMAC-SHA256(secret, "<t>.<body>"))>
+//
+// El receptor debe recalcular la firma con su secreto y rechazar timestamps viejos.
+//
+// Los endpoints solo pueden apuntar a direcciones públicas: la URL se valida al crearla
+// y la misma regla se aplica al conectar (por si el DNS cambia después). No se siguen redirecciones.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"sport-events-backend/internal/models"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+var (
+	ErrInvalidURL     = errors.New("debe ser una URL http(s) absoluta")
+	ErrUnresolvable   = errors.New("no se pudo resolver el host")
+	ErrBlockedAddress = errors.New("el host resuelve a una dirección no permitida (local o privada)")
+	errUnreachable    = errors.New("no se pudo conectar con el endpoint")
+	errTimeout        = errors.New("el endpoint no respondió a tiempo")
+)
+
+// cgnat: 100.64.0.0/10, espacio compartido de los proveedores (tampoco es público)
+var cgnat = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// blockedIP: loopback, redes privadas (RFC 1918 / ULA / CGNAT), link-local (incluye la IP de
+// metadatos 169.254.169.254), multicast, 0.0.0.0/8 y la dirección no especificada
+func blockedIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil && (ip4[0] == 0 || cgnat.Contains(ip4)) {
+		return true
+	}
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast()
+}
+
+// ValidateURL comprueba que la URL sea http(s) y que todas las IPs del host sean públicas
+func ValidateURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidURL
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		if blockedIP(ip) {
+			return ErrBlockedAddress
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return ErrUnresolvable
+	}
+	for _, a := range addrs {
+		if blockedIP(a.IP) {
+			return ErrBlockedAddress
+		}
+	}
+	return nil
+}
+
+// dialControl rechaza la conexión si la IP resuelta no es pública (evita DNS rebinding)
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || blockedIP(ip) {
+		return ErrBlockedAddress
+	}
+	return nil
+}
+
+// NewSecret genera el secreto de firma de un endpoint
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign devuelve el valor de X-Webhook-Signature para el cuerpo enviado en el instante ts
+func Sign(secret string, ts time.Time, body []byte) string {
+	t := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sender hace los POST firmados
+type Sender struct {
+	Client *http.Client
+}
+
+// NewSender arma un cliente que solo conecta con IPs públicas, sin proxy y sin seguir redirecciones
+func NewSender() Sender {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: dialControl}
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	return Sender{Client: &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// Send entrega el webhook. Devuelve el código HTTP de la respuesta (0 si no hubo respuesta);
+// cualquier código fuera de 2xx (también una redirección) es un error y se reintenta.
+// El error devuelto se muestra al organizador, así que no incluye el detalle de la conexión.
+func (s Sender) Send(ctx context.Context, d models.WebhookDeliveryTarget) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sport-events-webhooks/1")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, strconv.Itoa(d.ID))
+	req.Header.Set(HeaderSignature, Sign(d.Secret, time.Now(), d.Payload))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		log.Printf("⚠️ Error entregando el webhook %d: %v", d.ID, err)
+		return 0, publicError(err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("el endpoint respondió %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// publicError resume un error de transporte sin exponer direcciones ni mensajes internos
+func publicError(err error) error {
+	if errors.Is(err, ErrBlockedAddress) {
+		return ErrBlockedAddress
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return errTimeout
+	}
+	return errUnreachable
+}
@@ -0,0 +1,102 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestBlockedIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"127.1.2.3", true},
+		{"10.0.0.1", true},
+		{"10.255.255.255", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"100.64.0.1", true},
+		{"100.127.255.254", true},
+		{"169.254.169.254", true},
+		{"0.0.0.0", true},
+		{"0.1.2.3", true},
+		{"224.0.0.1", true},
+		{"::1", true},
+		{"::", true},
+		{"fc00::1", true},
+		{"fd12:3456:789a::1", true},
+		{"fe80::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:10.0.0.1", true},
+		{"8.8.8.8", false},
+		{"100.128.0.1", false},
+		{"172.32.0.1", false},
+		{"2001:4860:4860::8888", false},
+	}
+	for _, tt := range tests {
+		ip := net.ParseIP(tt.ip)
+		if ip == nil {
+			t.Fatalf("IP de prueba inválida: %s", tt.ip)
+		}
+		if got := blockedIP(ip); got != tt.want {
+			t.Errorf("blockedIP(%s) = %v, se esperaba %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want error
+	}{
+		{"http://127.0.0.1/hook", ErrBlockedAddress},
+		{"http://10.1.2.3:8080/hook", ErrBlockedAddress},
+		{"https://100.64.0.1/hook", ErrBlockedAddress},
+		{"http://169.254.169.254/latest/meta-data/", ErrBlockedAddress},
+		{"http://[::1]/hook", ErrBlockedAddress},
+		{"http://[fc00::1]/hook", ErrBlockedAddress},
+		{"http://0.0.0.0/hook", ErrBlockedAddress},
+		{"https://8.8.8.8/hook", nil},
+		{"https://[2001:4860:4860::8888]/hook", nil},
+		{"ftp://8.8.8.8/hook", ErrInvalidURL},
+		{"/solo/ruta", ErrInvalidURL},
+		{"https://", ErrInvalidURL},
+		{"no es una url", ErrInvalidURL},
+	}
+	for _, tt := range tests {
+		if got := ValidateURL(context.Background(), tt.url); !errors.Is(got, tt.want) {
+			t.Errorf("ValidateURL(%q) = %v, se esperaba %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestDialControl(t *testing.T) {
+	tests := []struct {
+		address string
+		want    error
+	}{
+		{"127.0.0.1:80", ErrBlockedAddress},
+		{"169.254.169.254:80", ErrBlockedAddress},
+		{"[fc00::1]:443", ErrBlockedAddress},
+		{"8.8.8.8:443", nil},
+	}
+	for _, tt := range tests {
+		if got := dialControl("tcp", tt.address, nil); !errors.Is(got, tt.want) {
+			t.Errorf("dialControl(%s) = %v, se esperaba %v", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Vector calculado aparte con:
+	// printf '1700000000.{"type":"checkin.recorded"}' | openssl dgst -sha256 -hmac whsec_test
+	got := Sign("whsec_test", time.Unix(1700000000, 0), []byte(`{"type":"checkin.recorded"}`))
+	want := "t=1700000000,v1=bd51dc44a7313aae50fe20555983e29eaa71f71d1a02f0d5916bfa91350c4ecb"
+	if got != want {
+		t.Errorf("Sign = %s, se esperaba %s", got, want)
+	}
+}
@@ -0,0 +1,14 @@
+-- migrations/006_events_capacity_waitlist.sql
+ALTER TABLE events
+  ADD COLUMN capacity INT NULL CHECK (capacity IS NULL OR capacity >= 0); -- NULL = sin límite
+
+CREATE TABLE IF NOT EXISTS waitlist (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT uniq_waitlist_user_event UNIQUE (user_id, event_id)
+);
+
+-- La posición en la lista se calcula por orden de llegada
+CREATE INDEX IF NOT EXISTS idx_waitlist_event_created ON waitlist(event_id, created_at, id);
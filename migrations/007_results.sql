@@ -0,0 +1,19 @@
+-- migrations/007_results.sql
+-- Resultados calculados a partir de los checkins (se recalculan con cada checkin)
+CREATE TABLE IF NOT EXISTS results (
+  event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  status VARCHAR(20) NOT NULL,           -- finished | running | incomplete
+  overall_rank INT NULL,                 -- solo para status = 'finished'
+  started_at TIMESTAMP NULL,
+  finished_at TIMESTAMP NULL,
+  elapsed_ms BIGINT NULL,
+  distance_m DOUBLE PRECISION NULL,
+  pace_s_per_km DOUBLE PRECISION NULL,
+  splits JSONB NOT NULL DEFAULT '[]',
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_results_event_rank ON results(event_id, overall_rank);
+CREATE INDEX IF NOT EXISTS idx_checkins_event ON checkins(event_id, user_id, created_at);
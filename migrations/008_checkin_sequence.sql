@@ -0,0 +1,29 @@
+-- migrations/008_checkin_sequence.sql
+-- Modo de validación del orden de checkpoints por evento:
+--   strict  → hay que pasar por cada checkpoint en orden, sin saltarse ninguno
+--   lenient → se permite saltar checkpoints, pero no retroceder ni repetir
+ALTER TABLE events
+  ADD COLUMN checkin_mode VARCHAR(10) NOT NULL DEFAULT 'strict'
+  CHECK (checkin_mode IN ('strict', 'lenient'));
+
+-- Los checkins repetidos (se conserva el primero) se mueven a checkins_duplicates
+-- antes de crear la restricción, para que un operador pueda revisarlos
+CREATE TABLE IF NOT EXISTS checkins_duplicates (
+  LIKE checkins,
+  archived_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+WITH moved AS (
+  DELETE FROM checkins a
+  USING checkins b
+  WHERE a.user_id = b.user_id
+    AND a.event_id = b.event_id
+    AND a.checkpoint_id = b.checkpoint_id
+    AND a.id > b.id
+  RETURNING a.*
+)
+INSERT INTO checkins_duplicates
+SELECT * FROM moved;
+
+ALTER TABLE checkins
+ADD CONSTRAINT uniq_checkin_user_event_checkpoint UNIQUE (user_id, event_id, checkpoint_id);
@@ -0,0 +1,8 @@
+-- migrations/009_checkpoint_radius_accuracy.sql
+-- Radio por defecto del evento (cada checkpoint puede definir su propio radius_m en la ruta)
+ALTER TABLE events
+  ADD COLUMN checkpoint_radius_m DOUBLE PRECISION NULL CHECK (checkpoint_radius_m IS NULL OR checkpoint_radius_m > 0);
+
+-- Precisión GPS reportada por el dispositivo al hacer checkin
+ALTER TABLE checkins
+  ADD COLUMN accuracy_m DOUBLE PRECISION NULL;
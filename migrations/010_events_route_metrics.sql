@@ -0,0 +1,8 @@
+-- migrations/010_events_route_metrics.sql
+-- Métricas calculadas a partir de la ruta (se recalculan al guardar la ruta)
+ALTER TABLE events
+  ADD COLUMN distance_m DOUBLE PRECISION NULL,
+  ADD COLUMN elevation_gain_m DOUBLE PRECISION NULL,
+  ADD COLUMN elevation_loss_m DOUBLE PRECISION NULL;
+
+CREATE INDEX IF NOT EXISTS idx_events_distance ON events(distance_m);
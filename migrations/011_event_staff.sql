@@ -0,0 +1,17 @@
+-- migrations/011_event_staff.sql
+-- Staff/voluntarios asignados por el organizador a un evento, opcionalmente a un checkpoint
+CREATE TABLE IF NOT EXISTS event_staff (
+  id SERIAL PRIMARY KEY,
+  event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  checkpoint_id INT NULL, -- NULL = todos los checkpoints del evento
+  assigned_by INT NOT NULL REFERENCES users(id),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_event_staff_assignment
+  ON event_staff(event_id, user_id, COALESCE(checkpoint_id, 0));
+
+-- Quién registró el checkin cuando lo hace un staff en nombre del corredor
+ALTER TABLE checkins
+  ADD COLUMN recorded_by INT NULL REFERENCES users(id);
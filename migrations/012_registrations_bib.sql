@@ -0,0 +1,27 @@
+-- migrations/012_registrations_bib.sql
+-- Dorsal asignado a cada inscripción (único por evento)
+ALTER TABLE registrations
+  ADD COLUMN bib_number INT NULL CHECK (bib_number IS NULL OR bib_number > 0);
+
+ALTER TABLE registrations
+  ADD CONSTRAINT uniq_registration_event_bib UNIQUE (event_id, bib_number);
+
+-- Rango de dorsales del evento (NULL = desde 1, sin tope)
+ALTER TABLE events
+  ADD COLUMN bib_range_start INT NULL CHECK (bib_range_start IS NULL OR bib_range_start > 0),
+  ADD COLUMN bib_range_end INT NULL,
+  ADD CONSTRAINT chk_events_bib_range CHECK (bib_range_end IS NULL OR bib_range_end >= COALESCE(bib_range_start, 1));
+
+-- Numerar las inscripciones existentes por evento en orden de inscripción,
+-- desde el inicio del rango (sin rango, desde 1); las que excedan el tope quedan sin dorsal
+UPDATE registrations r
+SET bib_number = numbered.bib
+FROM (
+  SELECT r2.id, COALESCE(e.bib_range_start, 1) + ROW_NUMBER() OVER (PARTITION BY r2.event_id ORDER BY r2.date, r2.id) - 1 AS bib,
+         e.bib_range_end
+  FROM registrations r2
+  JOIN events e ON e.id = r2.event_id
+  WHERE r2.bib_number IS NULL
+) numbered
+WHERE r.id = numbered.id
+  AND (numbered.bib_range_end IS NULL OR numbered.bib <= numbered.bib_range_end);
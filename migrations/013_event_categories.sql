@@ -0,0 +1,30 @@
+-- migrations/013_event_categories.sql
+-- Categorías / distancias de un evento (5K, 10K, 21K, 42K...)
+CREATE TABLE IF NOT EXISTS event_categories (
+  id SERIAL PRIMARY KEY,
+  event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  distance_m DOUBLE PRECISION NULL,
+  route JSONB NULL,                                              -- NULL = usa la ruta del evento
+  capacity INT NULL CHECK (capacity IS NULL OR capacity >= 0),   -- NULL = sin límite propio
+  start_time TIMESTAMP NULL,                                     -- NULL = hora del evento
+  price NUMERIC(10, 2) NULL CHECK (price IS NULL OR price >= 0),
+  bib_range_start INT NULL CHECK (bib_range_start IS NULL OR bib_range_start > 0),
+  bib_range_end INT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT uniq_event_category_name UNIQUE (event_id, name),
+  CONSTRAINT chk_event_categories_bib_range CHECK (bib_range_end IS NULL OR bib_range_end >= COALESCE(bib_range_start, 1))
+);
+
+-- Categoría elegida al inscribirse (NULL en eventos sin categorías)
+ALTER TABLE registrations
+  ADD COLUMN category_id INT NULL REFERENCES event_categories(id) ON DELETE RESTRICT;
+
+ALTER TABLE waitlist
+  ADD COLUMN category_id INT NULL REFERENCES event_categories(id) ON DELETE CASCADE;
+
+-- La clasificación se calcula por categoría
+ALTER TABLE results
+  ADD COLUMN category_id INT NULL REFERENCES event_categories(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_registrations_category ON registrations(category_id);
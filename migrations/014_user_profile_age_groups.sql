@@ -0,0 +1,10 @@
+-- migrations/014_user_profile_age_groups.sql
+-- Datos del corredor para clasificaciones por edad y género
+ALTER TABLE users
+  ADD COLUMN birthdate DATE NULL,
+  ADD COLUMN gender VARCHAR(10) NULL CHECK (gender IS NULL OR gender IN ('female', 'male', 'other'));
+
+-- Grupos de edad del evento: [{"name":"18-29","min_age":18,"max_age":29}, ...]
+-- NULL = grupos por defecto
+ALTER TABLE events
+  ADD COLUMN age_groups JSONB NULL;
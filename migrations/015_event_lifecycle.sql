@@ -0,0 +1,29 @@
+-- migrations/015_event_lifecycle.sql
+-- Máquina de estados del evento:
+-- draft → published → registration_open → registration_closed → live → completed
+-- (+ cancelled / postponed). Las transiciones válidas se controlan en internal/lifecycle.
+
+-- Los eventos existentes estaban abiertos a inscripción; los ya pasados se dan por terminados
+UPDATE events SET status = 'completed' WHERE status = 'scheduled' AND date < NOW();
+UPDATE events SET status = 'registration_open' WHERE status = 'scheduled';
+
+ALTER TABLE events
+  ALTER COLUMN status SET DEFAULT 'draft',
+  ADD COLUMN status_changed_at TIMESTAMP NULL,
+  ADD CONSTRAINT chk_events_status CHECK (status IN (
+    'draft', 'published', 'registration_open', 'registration_closed',
+    'live', 'completed', 'cancelled', 'postponed'
+  ));
+
+-- Historial de transiciones (una fila por cambio de estado)
+CREATE TABLE IF NOT EXISTS event_status_transitions (
+  id SERIAL PRIMARY KEY,
+  event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  from_status VARCHAR(20) NOT NULL,
+  to_status VARCHAR(20) NOT NULL,
+  changed_by INT NULL REFERENCES users(id) ON DELETE SET NULL, -- NULL = sistema
+  reason TEXT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_status_transitions_event ON event_status_transitions(event_id, created_at);
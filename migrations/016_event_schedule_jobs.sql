@@ -0,0 +1,11 @@
+-- migrations/016_event_schedule_jobs.sql
+-- Ventana de inscripción (la abren/cierran los jobs programados)
+ALTER TABLE events
+  ADD COLUMN registration_opens_at TIMESTAMP NULL,  -- NULL = apertura manual
+  ADD COLUMN registration_closes_at TIMESTAMP NULL, -- NULL = se cierra al empezar el evento
+  ADD COLUMN results_finalized_at TIMESTAMP NULL,
+  ADD CONSTRAINT chk_events_registration_window CHECK (
+    registration_opens_at IS NULL OR registration_closes_at IS NULL OR registration_opens_at < registration_closes_at
+  );
+
+CREATE INDEX IF NOT EXISTS idx_events_status_date ON events(status, date);
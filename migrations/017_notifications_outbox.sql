@@ -0,0 +1,22 @@
+-- migrations/017_notifications_outbox.sql
+-- Outbox de notificaciones: se escriben en la misma transacción que el cambio que las
+-- origina y un job las entrega por el canal correspondiente (con reintentos).
+CREATE TABLE IF NOT EXISTS notifications (
+  id SERIAL PRIMARY KEY,
+  event_id INT NULL REFERENCES events(id) ON DELETE CASCADE,
+  user_id INT NULL REFERENCES users(id) ON DELETE CASCADE,  -- NULL = no va dirigida a un usuario (p.ej. webhook)
+  channel VARCHAR(20) NOT NULL,                             -- email | webhook
+  kind VARCHAR(40) NOT NULL,                                -- event_cancelled | event_postponed | event_updated
+  recipient TEXT NOT NULL,                                  -- email o URL
+  payload JSONB NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'sent', 'failed')),
+  attempts INT NOT NULL DEFAULT 0,
+  max_attempts INT NOT NULL DEFAULT 5,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  last_error TEXT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  sent_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_due ON notifications(next_attempt_at) WHERE status IN ('pending', 'sending');
+CREATE INDEX IF NOT EXISTS idx_notifications_event ON notifications(event_id, created_at);
@@ -0,0 +1,8 @@
+-- migrations/018_user_locale.sql
+-- Idioma de los emails del usuario
+ALTER TABLE users
+  ADD COLUMN locale VARCHAR(5) NOT NULL DEFAULT 'es' CHECK (locale IN ('es', 'en'));
+
+-- Idioma con el que se renderiza cada notificación (copiado del usuario al encolarla)
+ALTER TABLE notifications
+  ADD COLUMN locale VARCHAR(5) NOT NULL DEFAULT 'es';
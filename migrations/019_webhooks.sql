@@ -0,0 +1,34 @@
+-- migrations/019_webhooks.sql
+-- Webhooks de organizadores: por evento o para todos sus eventos (event_id NULL)
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+  id SERIAL PRIMARY KEY,
+  owner_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  event_id INT NULL REFERENCES events(id) ON DELETE CASCADE,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,                          -- clave HMAC-SHA256 de la firma
+  event_types TEXT[] NOT NULL DEFAULT '{}',      -- vacío = todos los tipos
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_owner ON webhook_endpoints(owner_id);
+
+-- Log de entregas (outbox): se escriben en la transacción del cambio y un job las envía
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id SERIAL PRIMARY KEY,
+  endpoint_id INT NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+  event_id INT NULL REFERENCES events(id) ON DELETE SET NULL,
+  event_type VARCHAR(50) NOT NULL,
+  payload JSONB NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'delivered', 'failed')),
+  attempts INT NOT NULL DEFAULT 0,
+  max_attempts INT NOT NULL DEFAULT 8,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  response_status INT NULL,
+  last_error TEXT NULL,
+  replay_of INT NULL REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  delivered_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status IN ('pending', 'sending');
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint ON webhook_deliveries(endpoint_id, created_at);
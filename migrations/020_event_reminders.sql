@@ -0,0 +1,25 @@
+-- migrations/020_event_reminders.sql
+-- Recordatorios antes del evento, configurados por el organizador (p.ej. 7 días, 1 día, 2 horas)
+CREATE TABLE IF NOT EXISTS event_reminders (
+  id SERIAL PRIMARY KEY,
+  event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  offset_minutes INT NOT NULL CHECK (offset_minutes > 0),  -- minutos antes de events.date
+  message TEXT NULL,                                       -- texto extra (retiro de kits, horario de salida...)
+  sent_at TIMESTAMP NULL,                                  -- NULL = pendiente; se reinicia si cambia la fecha
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (event_id, offset_minutes)
+);
+
+-- Preferencias de notificación por usuario (sin fila = valores por defecto)
+CREATE TABLE IF NOT EXISTS notification_preferences (
+  user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  email_reminders BOOLEAN NOT NULL DEFAULT TRUE,
+  email_event_updates BOOLEAN NOT NULL DEFAULT TRUE,  -- cancelación, aplazamiento, cambio de fecha o lugar
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Bandeja de entrada en la app: notificaciones con channel = 'inbox' (se crean ya como 'sent')
+ALTER TABLE notifications
+  ADD COLUMN read_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_inbox ON notifications(user_id, created_at) WHERE channel = 'inbox';
@@ -0,0 +1,17 @@
+-- migrations/021_password_resets.sql
+-- Tokens de restablecimiento de contraseña: solo se guarda el SHA-256 del token enviado por email
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  expires_at TIMESTAMP NOT NULL,
+  used_at TIMESTAMP NULL,          -- un solo uso; también se marcan al usar otro token del usuario
+  requested_ip TEXT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user ON password_reset_tokens(user_id, created_at);
+
+-- Último cambio de contraseña (informativo; el cambio revoca las sesiones abiertas)
+ALTER TABLE users
+  ADD COLUMN password_changed_at TIMESTAMP NULL;
@@ -0,0 +1,19 @@
+-- migrations/022_email_verification.sql
+-- Verificación de email: sin verificar no se puede inscribir ni usar funciones de organizador
+ALTER TABLE users
+  ADD COLUMN email_verified_at TIMESTAMP NULL;
+
+-- Las cuentas existentes se dan por verificadas
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+-- Tokens del enlace de verificación (solo se guarda el SHA-256)
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  expires_at TIMESTAMP NOT NULL,
+  used_at TIMESTAMP NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user ON email_verification_tokens(user_id, created_at);
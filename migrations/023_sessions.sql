@@ -0,0 +1,19 @@
+-- migrations/023_sessions.sql
+-- Sesiones de login: el access token (JWT corto) lleva el ID de sesión ("sid") y el
+-- refresh token rota en cada uso. Solo se guardan los SHA-256 de los refresh tokens.
+CREATE TABLE IF NOT EXISTS sessions (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  refresh_token_hash CHAR(64) NOT NULL UNIQUE,
+  previous_token_hash CHAR(64) NULL,      -- el refresh token anterior: si se reutiliza, se revoca la sesión
+  user_agent TEXT NULL,
+  ip TEXT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  last_used_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMP NOT NULL,
+  revoked_at TIMESTAMP NULL,
+  revoked_reason VARCHAR(40) NULL          -- logout | logout_all | password_reset | token_reuse | ...
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(user_id) WHERE revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_sessions_previous_token ON sessions(previous_token_hash);
@@ -0,0 +1,23 @@
+-- migrations/024_admin_users.sql
+-- Rol admin, suspensión de cuentas y solicitudes para ser organizador
+ALTER TABLE users
+  ADD COLUMN suspended_at TIMESTAMP NULL,
+  ADD COLUMN suspended_reason TEXT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
+
+CREATE TABLE IF NOT EXISTS organizer_applications (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  organization VARCHAR(150) NULL,
+  message TEXT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+  reviewed_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+  reviewed_at TIMESTAMP NULL,
+  review_note TEXT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Como mucho una solicitud pendiente por usuario
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organizer_applications_pending
+  ON organizer_applications(user_id) WHERE status = 'pending';
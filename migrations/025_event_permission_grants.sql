@@ -0,0 +1,13 @@
+-- migrations/025_event_permission_grants.sql
+-- Permisos concedidos a un usuario sobre un evento concreto (además de los del dueño y el staff)
+CREATE TABLE IF NOT EXISTS event_permission_grants (
+  id SERIAL PRIMARY KEY,
+  event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  permission VARCHAR(50) NOT NULL,
+  granted_by INT NOT NULL REFERENCES users(id),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (event_id, user_id, permission)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_permission_grants_user ON event_permission_grants(user_id, event_id);
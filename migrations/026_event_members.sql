@@ -0,0 +1,39 @@
+-- migrations/026_event_members.sql
+-- Equipo del evento: miembros con rol e invitaciones por email
+CREATE TABLE IF NOT EXISTS event_members (
+  id SERIAL PRIMARY KEY,
+  event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'co_organizer', 'viewer', 'staff')),
+  added_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_members_user ON event_members(user_id);
+
+-- Un único owner por evento
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_members_owner
+  ON event_members(event_id) WHERE role = 'owner';
+
+-- El creador de cada evento existente pasa a ser su owner
+INSERT INTO event_members (event_id, user_id, role, added_by)
+SELECT id, created_by, 'owner', created_by FROM events
+ON CONFLICT (event_id, user_id) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS event_invitations (
+  id SERIAL PRIMARY KEY,
+  event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  email VARCHAR(255) NOT NULL,
+  role VARCHAR(20) NOT NULL CHECK (role IN ('co_organizer', 'viewer', 'staff')),
+  token_hash CHAR(64) NOT NULL UNIQUE, -- el token en claro solo viaja en el email
+  invited_by INT NOT NULL REFERENCES users(id),
+  expires_at TIMESTAMP NOT NULL,
+  accepted_at TIMESTAMP NULL,
+  accepted_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Como mucho una invitación pendiente por email y evento (invitar de nuevo la reemplaza)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_invitations_pending
+  ON event_invitations(event_id, LOWER(email)) WHERE accepted_at IS NULL;